@@ -23,8 +23,22 @@ func main() {
 	docs.SwaggerInfo.BasePath = "/"
 
        router.GET("/ws", handler.WSHandler)
+       router.GET("/ws/solo", handler.SoloWSHandler)
        router.GET("/api/youtube/test", handler.YouTubeTestHandler)
        router.GET("/api/youtube/embeddable/:videoId", handler.CheckEmbeddableHandler)
+       router.POST("/api/youtube/embeddable", handler.CheckEmbeddableBatchHandler)
+       router.GET("/api/youtube/cache", handler.YouTubeCacheStatsHandler)
+       router.GET("/api/youtube/quota", handler.YouTubeQuotaHandler)
+       router.GET("/api/ratings", handler.ListRatingsHandler)
+       router.GET("/api/ratings/:user", handler.GetRatingHandler)
+       router.GET("/api/matches", handler.ListMatchesHandler)
+       router.GET("/api/matches/:matchId", handler.GetMatchHandler)
+       router.GET("/api/daily/leaderboard", handler.DailyLeaderboardHandler)
+       router.GET("/api/challenges/:code", handler.GetChallengeHandler)
+       router.GET("/api/packs", handler.ListPacksHandler)
+       router.GET("/api/aliases", handler.ListAliasesHandler)
+       router.GET("/api/aliases/:videoId", handler.GetAliasesHandler)
+       router.PUT("/api/aliases/:videoId", handler.PutAliasesHandler)
        router.GET("/api/hello", handler.HelloHandler)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -11,14 +12,163 @@ import (
 // TimeLimit defines the countdown duration in seconds.
 var TimeLimit = 10
 
+// QuestionCount defines how many questions are played in one game.
+var QuestionCount = 10
+
+// DataDir is the directory where persistent data such as ratings is stored.
+var DataDir = "data"
+
+// ChatMaxLength is the maximum number of characters in a chat message.
+var ChatMaxLength = 200
+
+// ChatRateLimit is the number of chat messages a user may send per ChatRateWindow seconds.
+var ChatRateLimit = 5
+
+// ChatRateWindow is the length of the chat rate limiting window in seconds.
+var ChatRateWindow = 10
+
+// ReactionInterval is how often aggregated reactions are broadcast, in milliseconds.
+var ReactionInterval = 1000
+
+// RevealDuration is how long the answer is shown after each question, in seconds.
+var RevealDuration = 5
+
+// IntermissionDuration is the break between the reveal and the next question, in seconds.
+var IntermissionDuration = 3
+
+// AutoAdvance starts the next question after the intermission without waiting for everyone to be ready.
+var AutoAdvance = false
+
+// DailyPlaylistID is the playlist the daily challenge draws its tracks from.
+var DailyPlaylistID = ""
+
+// DailyMaxOffset is the latest clip start, in seconds, used by the daily challenge.
+var DailyMaxOffset = 30
+
+// PackDir is the directory question packs are loaded from.
+var PackDir = "packs"
+
+// AnswerStrictness is the default answer matching level: lenient, normal or strict.
+var AnswerStrictness = "normal"
+
+// AnswerJudge is the default answer judge: contains, exact, fuzzy or normalized.
+var AnswerJudge = "fuzzy"
+
+// JudgeTimeout is how long the host has to judge an answer before the automatic judge decides, in seconds.
+var JudgeTimeout = 15
+
+// AppealWindow is how long a wrong answer can be appealed, and how long players vote on an appeal, in seconds.
+var AppealWindow = 10
+
+// YouTubeAPIKey is the key used for YouTube Data API requests.
+var YouTubeAPIKey = ""
+
+// YouTubeBaseURL is the base URL of the YouTube Data API.
+var YouTubeBaseURL = "https://www.googleapis.com"
+
+// YouTubeTimeout is the timeout of a single YouTube API request, in seconds.
+var YouTubeTimeout = 10
+
+// CachePlaylistTTL is how long fetched playlist contents are reused, in seconds.
+var CachePlaylistTTL = 3600
+
+// CacheVideoTTL is how long the fetched status of a video is reused, in seconds.
+var CacheVideoTTL = 86400
+
+// CacheMaxPlaylists is the number of playlists kept in the cache.
+var CacheMaxPlaylists = 100
+
+// CacheMaxVideos is the number of video statuses kept in the cache.
+var CacheMaxVideos = 10000
+
+// CachePersist keeps the YouTube cache in DataDir across restarts.
+var CachePersist = false
+
+// YouTubeQuotaBudget is the number of YouTube API quota units that may be spent per day.
+var YouTubeQuotaBudget = 10000
+
+// YouTubeQuotaWarn is the share of the quota budget, in percent, at which a warning is logged.
+var YouTubeQuotaWarn = 80
+
+// QuotaFallbackPack is the question pack played when the quota is exhausted
+// and a playlist is not cached. Empty picks the first pack.
+var QuotaFallbackPack = ""
+
+// YouTubeRegion is the country, as an ISO 3166-1 alpha-2 code, videos must be
+// playable in. Empty disables the region check.
+var YouTubeRegion = "JP"
+
+// AdminKey is the key required in the X-Admin-Key header to edit answer
+// aliases. Empty makes the aliases read-only.
+var AdminKey = ""
+
+// TitleRulesFile is a JSON file with custom title cleaning rules per playlist.
+var TitleRulesFile = ""
+
 // LoadEnv loads environment variables from a .env file.
 func LoadEnv() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("no .env file found")
 	}
-	if v := os.Getenv("TIME_LIMIT"); v != "" {
+	loadPositiveInt("TIME_LIMIT", &TimeLimit)
+	loadPositiveInt("QUESTION_COUNT", &QuestionCount)
+	if v := os.Getenv("DATA_DIR"); v != "" {
+		DataDir = v
+	}
+	loadPositiveInt("CHAT_MAX_LENGTH", &ChatMaxLength)
+	loadPositiveInt("CHAT_RATE_LIMIT", &ChatRateLimit)
+	loadPositiveInt("CHAT_RATE_WINDOW", &ChatRateWindow)
+	loadPositiveInt("REACTION_INTERVAL_MS", &ReactionInterval)
+	loadPositiveInt("REVEAL_DURATION", &RevealDuration)
+	loadPositiveInt("INTERMISSION_DURATION", &IntermissionDuration)
+	DailyPlaylistID = os.Getenv("DAILY_PLAYLIST_ID")
+	loadPositiveInt("DAILY_MAX_OFFSET", &DailyMaxOffset)
+	if v := os.Getenv("PACK_DIR"); v != "" {
+		PackDir = v
+	}
+	TitleRulesFile = os.Getenv("TITLE_RULES_FILE")
+	loadPositiveInt("JUDGE_TIMEOUT", &JudgeTimeout)
+	loadPositiveInt("APPEAL_WINDOW", &AppealWindow)
+	YouTubeAPIKey = os.Getenv("YOUTUBE_API_KEY")
+	if v := os.Getenv("YOUTUBE_API_BASE_URL"); v != "" {
+		YouTubeBaseURL = v
+	}
+	loadPositiveInt("YOUTUBE_TIMEOUT", &YouTubeTimeout)
+	if v, ok := os.LookupEnv("YOUTUBE_REGION"); ok {
+		YouTubeRegion = strings.ToUpper(strings.TrimSpace(v))
+	}
+	loadPositiveInt("YOUTUBE_QUOTA_BUDGET", &YouTubeQuotaBudget)
+	loadPositiveInt("YOUTUBE_QUOTA_WARN", &YouTubeQuotaWarn)
+	QuotaFallbackPack = os.Getenv("QUOTA_FALLBACK_PACK")
+	AdminKey = os.Getenv("ADMIN_KEY")
+	loadPositiveInt("CACHE_PLAYLIST_TTL", &CachePlaylistTTL)
+	loadPositiveInt("CACHE_VIDEO_TTL", &CacheVideoTTL)
+	loadPositiveInt("CACHE_MAX_PLAYLISTS", &CacheMaxPlaylists)
+	loadPositiveInt("CACHE_MAX_VIDEOS", &CacheMaxVideos)
+	if v := os.Getenv("CACHE_PERSIST"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			CachePersist = b
+		}
+	}
+	if v := os.Getenv("ANSWER_JUDGE"); v != "" {
+		AnswerJudge = v
+	}
+	switch v := os.Getenv("ANSWER_STRICTNESS"); v {
+	case "lenient", "normal", "strict":
+		AnswerStrictness = v
+	}
+	if v := os.Getenv("AUTO_ADVANCE"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			AutoAdvance = b
+		}
+	}
+}
+
+// loadPositiveInt overwrites dst with the environment variable if it holds a positive integer.
+func loadPositiveInt(key string, dst *int) {
+	if v := os.Getenv(key); v != "" {
 		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			TimeLimit = n
+			*dst = n
 		}
 	}
 }
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"intro-quiz/backend/internal/config"
+)
+
+// aliasRequest is the body of an alias update.
+type aliasRequest struct {
+	Aliases []string `json:"aliases"`
+}
+
+// ListAliasesHandler returns the accepted answer aliases of every video.
+// @Summary      List answer aliases
+// @Description  Retrieve the stored answer aliases keyed by video ID. Aliases defined in question packs are not included.
+// @Tags         aliases
+// @Produce      json
+// @Success      200 {object} map[string][]string
+// @Router       /api/aliases [get]
+func ListAliasesHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, roomManager.Aliases().List())
+}
+
+// GetAliasesHandler returns the accepted answer aliases of a video.
+// @Summary      Get answer aliases
+// @Description  Retrieve the stored answer aliases of a video.
+// @Tags         aliases
+// @Produce      json
+// @Param        videoId   path      string  true  "YouTube Video ID"
+// @Success      200 {object} map[string]interface{}
+// @Router       /api/aliases/{videoId} [get]
+func GetAliasesHandler(c *gin.Context) {
+	id := c.Param("videoId")
+	c.JSON(http.StatusOK, gin.H{"videoId": id, "aliases": roomManager.Aliases().Get(id)})
+}
+
+// PutAliasesHandler replaces the accepted answer aliases of a video. It
+// requires the admin key, so players cannot add answers for a live question.
+// @Summary      Set answer aliases
+// @Description  Replace the answer aliases of a video. An empty list removes them. Requires the X-Admin-Key header; aliases are read-only when no admin key is configured.
+// @Tags         aliases
+// @Accept       json
+// @Produce      json
+// @Param        videoId      path      string        true  "YouTube Video ID"
+// @Param        X-Admin-Key  header    string        true  "Admin key"
+// @Param        body         body      aliasRequest  true  "Aliases"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Failure      403 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /api/aliases/{videoId} [put]
+func PutAliasesHandler(c *gin.Context) {
+	key := c.GetHeader("X-Admin-Key")
+	if config.AdminKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(config.AdminKey)) != 1 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin key required"})
+		return
+	}
+	var req aliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	id := c.Param("videoId")
+	aliases, err := roomManager.Aliases().Set(id, req.Aliases)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"videoId": id, "aliases": aliases})
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"intro-quiz/backend/internal/service"
+)
+
+// GetChallengeHandler describes a recorded solo run by its share code. The
+// tracks stay hidden until the challenge has been played.
+// @Summary      Get challenge run
+// @Description  Retrieve who recorded a solo run, how many questions it has and when it was recorded.
+// @Tags         challenges
+// @Produce      json
+// @Param        code   path      string  true  "Challenge code"
+// @Success      200 {object} service.ChallengeSummary
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /api/challenges/{code} [get]
+func GetChallengeHandler(c *gin.Context) {
+	run, err := roomManager.Challenges().Get(c.Param("code"))
+	if errors.Is(err, service.ErrChallengeNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, run.Summary())
+}
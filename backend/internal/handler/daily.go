@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DailyLeaderboardHandler returns today's daily challenge leaderboard.
+// @Summary      Get daily leaderboard
+// @Description  Retrieve the score of each player's first game in today's daily challenge. The leaderboard resets at midnight JST.
+// @Tags         daily
+// @Produce      json
+// @Success      200 {object} service.DailyLeaderboard
+// @Router       /api/daily/leaderboard [get]
+func DailyLeaderboardHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, roomManager.Daily().Leaderboard())
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"intro-quiz/backend/internal/service"
+)
+
+// ListMatchesHandler returns summaries of all finished matches.
+// @Summary      List past matches
+// @Description  Retrieve summaries of finished games, newest first.
+// @Tags         matches
+// @Produce      json
+// @Success      200 {array} service.MatchSummary
+// @Failure      500 {object} map[string]string
+// @Router       /api/matches [get]
+func ListMatchesHandler(c *gin.Context) {
+	list, err := roomManager.History().List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetMatchHandler returns the full record of a finished match.
+// @Summary      Get past match
+// @Description  Retrieve players, questions, buzzes, answers and final scores of a finished game.
+// @Tags         matches
+// @Produce      json
+// @Param        matchId   path      string  true  "Match ID"
+// @Success      200 {object} service.MatchRecord
+// @Failure      404 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /api/matches/{matchId} [get]
+func GetMatchHandler(c *gin.Context) {
+	rec, err := roomManager.History().Get(c.Param("matchId"))
+	if errors.Is(err, service.ErrMatchNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rec)
+}
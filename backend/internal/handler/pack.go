@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPacksHandler returns the question packs that can be selected instead of a playlist.
+// @Summary      List question packs
+// @Description  Retrieve the name, track count and tags of every question pack in the pack directory.
+// @Tags         packs
+// @Produce      json
+// @Success      200 {array} service.PackSummary
+// @Failure      500 {object} map[string]string
+// @Router       /api/packs [get]
+func ListPacksHandler(c *gin.Context) {
+	list, err := roomManager.Packs().List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListRatingsHandler returns all player ratings ordered by strength.
+// @Summary      List player ratings
+// @Description  Retrieve the Elo-style ratings of all players from strongest to weakest.
+// @Tags         ratings
+// @Produce      json
+// @Success      200 {array} service.PlayerRating
+// @Router       /api/ratings [get]
+func ListRatingsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, roomManager.Ratings().List())
+}
+
+// GetRatingHandler returns the rating of a single player.
+// @Summary      Get player rating
+// @Description  Retrieve the Elo-style rating of a player by user name.
+// @Tags         ratings
+// @Produce      json
+// @Param        user   path      string  true  "User name"
+// @Success      200 {object} service.PlayerRating
+// @Failure      404 {object} map[string]string
+// @Router       /api/ratings/{user} [get]
+func GetRatingHandler(c *gin.Context) {
+	r, ok := roomManager.Ratings().Get(c.Param("user"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "rating not found"})
+		return
+	}
+	c.JSON(http.StatusOK, r)
+}
@@ -42,3 +42,24 @@ func WSHandler(c *gin.Context) {
 	client.Listen()
 	log.Printf("client disconnected: %s room:%s", conn.RemoteAddr(), roomID)
 }
+
+// SoloWSHandler starts a solo practice game over WebSocket.
+// @Summary      Solo practice WebSocket endpoint
+// @Description  Upgrade the request and play questions back-to-back without a room.
+// @Tags         websocket
+// @Success      101 {string} string "Switching Protocols"
+// @Router       /ws/solo [get]
+func SoloWSHandler(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("upgrade: %v", err)
+		return
+	}
+	svc := service.NewSoloService(conn, roomManager)
+	defer svc.Close()
+
+	client := ws.NewClient(conn, svc)
+	log.Printf("solo client connected: %s", conn.RemoteAddr())
+	client.Listen()
+	log.Printf("solo client disconnected: %s", conn.RemoteAddr())
+}
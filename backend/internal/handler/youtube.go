@@ -1,13 +1,21 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
-	"intro-quiz/backend/internal/service"
 )
 
+// maxBatchVideoIDs is the number of video IDs a batch embeddability check
+// accepts, four videos.list calls worth.
+const maxBatchVideoIDs = 200
+
+// embeddableRequest is the body of a batch embeddability check.
+type embeddableRequest struct {
+	VideoIDs []string `json:"videoIds" binding:"required"`
+}
+
 // YouTubeTestHandler returns the first video title of a fixed playlist.
 // @Summary      Get first video title
 // @Description  Retrieve the first video's title from a fixed YouTube playlist.
@@ -17,9 +25,7 @@ import (
 // @Failure      500 {object} map[string]string
 // @Router       /api/youtube/test [get]
 func YouTubeTestHandler(c *gin.Context) {
-	apiKey := os.Getenv("YOUTUBE_API_KEY")
-	svc := service.NewYouTubeService(apiKey)
-	title, err := svc.GetFirstVideoTitle("PLBCF2DAC6FFB574DE")
+	title, err := roomManager.YouTube().GetFirstVideoTitle(c.Request.Context(), "PLBCF2DAC6FFB574DE")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
 		return
@@ -29,7 +35,7 @@ func YouTubeTestHandler(c *gin.Context) {
 
 // CheckEmbeddableHandler reports whether a video can be embedded.
 // @Summary      Check if video is embeddable
-// @Description  Verify the embeddable status of a YouTube video.
+// @Description  Verify that a YouTube video is embeddable, not age restricted and available in the configured region.
 // @Tags         youtube
 // @Produce      json
 // @Param        videoId   path      string  true  "YouTube video ID"
@@ -43,10 +49,61 @@ func CheckEmbeddableHandler(c *gin.Context) {
                c.JSON(http.StatusBadRequest, gin.H{"error": "videoId required"})
                return
        }
-       ok, err := service.CheckEmbeddable(videoID)
+       ok, err := roomManager.YouTube().CheckEmbeddable(c.Request.Context(), videoID)
        if err != nil {
                c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
                return
        }
        c.JSON(http.StatusOK, gin.H{"embeddable": ok})
 }
+
+// CheckEmbeddableBatchHandler reports for several videos whether they can be embedded.
+// @Summary      Check if videos are embeddable
+// @Description  Verify for up to 200 YouTube videos that they are embeddable, not age restricted and available in the configured region, 50 per API request.
+// @Tags         youtube
+// @Accept       json
+// @Produce      json
+// @Param        body      body      embeddableRequest  true  "Video IDs"
+// @Success      200 {object} map[string]interface{}
+// @Failure      400 {object} map[string]string
+// @Failure      500 {object} map[string]string
+// @Router       /api/youtube/embeddable [post]
+func CheckEmbeddableBatchHandler(c *gin.Context) {
+	var req embeddableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.VideoIDs) > maxBatchVideoIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d videoIds per request", maxBatchVideoIDs)})
+		return
+	}
+	status, err := roomManager.YouTube().CheckEmbeddableBatch(c.Request.Context(), req.VideoIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"embeddable": status})
+}
+
+// YouTubeCacheStatsHandler returns the size and hit counts of the YouTube cache.
+// @Summary      Get YouTube cache statistics
+// @Description  Report how many playlists and video statuses are cached and how often the cache was hit.
+// @Tags         youtube
+// @Produce      json
+// @Success      200 {object} service.CacheStats
+// @Router       /api/youtube/cache [get]
+func YouTubeCacheStatsHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, roomManager.YouTube().Cache.Stats())
+}
+
+// YouTubeQuotaHandler returns the YouTube API quota spent today.
+// @Summary      Get YouTube quota usage
+// @Description  Report the quota units spent today per API call type against the configured budget.
+// @Tags         youtube
+// @Produce      json
+// @Success      200 {object} service.QuotaUsage
+// @Router       /api/youtube/quota [get]
+func YouTubeQuotaHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, roomManager.YouTube().Quota.Usage())
+}
@@ -2,20 +2,109 @@ package model
 
 // ClientMessage represents a message received from the client.
 type ClientMessage struct {
-	Type       string `json:"type"`
-	User       string `json:"user,omitempty"`
-	PlaylistID string `json:"playlistId,omitempty"`
-	Answer     string `json:"answer,omitempty"`
+	Type          string        `json:"type"`
+	User          string        `json:"user,omitempty"`
+	PlaylistID    string        `json:"playlistId,omitempty"`
+	Answer        string        `json:"answer,omitempty"`
+	ExcludePlayed bool          `json:"excludePlayed,omitempty"`
+	Text          string        `json:"text,omitempty"`
+	Emoji         string        `json:"emoji,omitempty"`
+	Settings      *RoomSettings `json:"settings,omitempty"`
+	Code          string        `json:"code,omitempty"`
+	Accept        bool          `json:"accept,omitempty"`
 }
 
 // ServerMessage represents a message sent to clients.
 type ServerMessage struct {
-	Type       string          `json:"type"`
-	User       string          `json:"user,omitempty"`
-	Timestamp  int64           `json:"timestamp"`
-	ReadyUsers map[string]bool `json:"readyUsers,omitempty"`
-	VideoID    string          `json:"videoId,omitempty"`
-	BuzzOrder  []string        `json:"buzzOrder,omitempty"`
-	VideoTitle string          `json:"videoTitle,omitempty"`
-	Correct    bool            `json:"correct,omitempty"`
+	Type         string          `json:"type"`
+	User         string          `json:"user,omitempty"`
+	Timestamp    int64           `json:"timestamp"`
+	ReadyUsers   map[string]bool `json:"readyUsers,omitempty"`
+	VideoID      string          `json:"videoId,omitempty"`
+	StartSeconds int             `json:"startSeconds,omitempty"`
+	BuzzOrder    []string        `json:"buzzOrder,omitempty"`
+	VideoTitle   string          `json:"videoTitle,omitempty"`
+	Correct      bool            `json:"correct,omitempty"`
+	Host         string          `json:"host,omitempty"`
+	Phase        string          `json:"phase,omitempty"`
+	Scores       map[string]int  `json:"scores,omitempty"`
+	Standings    []Standing      `json:"standings,omitempty"`
+	Ratings      map[string]int  `json:"ratings,omitempty"`
+	MatchID      string          `json:"matchId,omitempty"`
+	Text         string          `json:"text,omitempty"`
+	Reason       string          `json:"reason,omitempty"`
+	Reactions    map[string]int  `json:"reactions,omitempty"`
+	Channel      string          `json:"channel,omitempty"`
+	Outcome      string          `json:"outcome,omitempty"`
+	ReactionMs   int64           `json:"reactionMs,omitempty"`
+	Points       int             `json:"points,omitempty"`
+	Deadline     int64           `json:"deadline,omitempty"`
+	Settings     *RoomSettings   `json:"settings,omitempty"`
+	Tiebreak     *TiebreakResult `json:"tiebreak,omitempty"`
+	MatchedAlias string          `json:"matchedAlias,omitempty"`
+	Confidence   float64         `json:"confidence,omitempty"`
+	Summary      *SoloSummary    `json:"summary,omitempty"`
+	Votes        map[string]int  `json:"votes,omitempty"`
+}
+
+// SoloSummary reports the result of a solo practice game.
+type SoloSummary struct {
+	Questions     int                  `json:"questions"`
+	Correct       int                  `json:"correct"`
+	Accuracy      float64              `json:"accuracy"`
+	AvgReactionMs int64                `json:"avgReactionMs"`
+	ChallengeCode string               `json:"challengeCode,omitempty"`
+	Comparison    *ChallengeComparison `json:"comparison,omitempty"`
+}
+
+// ChallengeComparison lines up a replayed challenge against the original run.
+type ChallengeComparison struct {
+	Code              string          `json:"code"`
+	Original          string          `json:"original"`
+	Challenger        string          `json:"challenger"`
+	OriginalCorrect   int             `json:"originalCorrect"`
+	ChallengerCorrect int             `json:"challengerCorrect"`
+	Rows              []ComparisonRow `json:"rows"`
+}
+
+// ComparisonRow compares both runs on a single question.
+type ComparisonRow struct {
+	Round      int       `json:"round"`
+	VideoID    string    `json:"videoId"`
+	VideoTitle string    `json:"videoTitle"`
+	Original   RunResult `json:"original"`
+	Challenger RunResult `json:"challenger"`
+}
+
+// RunResult is how one player did on a question of a challenge.
+type RunResult struct {
+	Correct    bool   `json:"correct"`
+	ReactionMs int64  `json:"reactionMs,omitempty"`
+	Outcome    string `json:"outcome,omitempty"`
+}
+
+// TiebreakResult describes a sudden-death tiebreaker between players tied for first.
+type TiebreakResult struct {
+	Players    []string `json:"players"`
+	Eliminated []string `json:"eliminated,omitempty"`
+	Winner     string   `json:"winner,omitempty"`
+}
+
+// RoomSettings holds options the host can change for a room.
+type RoomSettings struct {
+	// AutoAdvance starts the next question after the intermission without a ready check.
+	AutoAdvance bool `json:"autoAdvance"`
+	// Strictness is the answer matching level: lenient, normal or strict.
+	Strictness string `json:"strictness,omitempty"`
+	// Judge selects how answers are checked: contains, exact, fuzzy or normalized.
+	Judge string `json:"judge,omitempty"`
+	// HostJudging lets the host accept or reject typed answers instead of the judge.
+	HostJudging bool `json:"hostJudging"`
+}
+
+// Standing is a player's final placement in a game.
+type Standing struct {
+	User  string `json:"user"`
+	Score int    `json:"score"`
+	Rank  int    `json:"rank"`
 }
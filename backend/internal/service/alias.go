@@ -0,0 +1,112 @@
+package service
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// AliasStore keeps accepted answer aliases per video ID. It complements the
+// aliases defined in question packs and can be edited while the server runs.
+type AliasStore struct {
+	mu      sync.Mutex
+	once    sync.Once
+	path    string
+	aliases map[string][]string
+}
+
+// NewAliasStore creates an AliasStore. Aliases are loaded lazily from the
+// configured data directory on first use.
+func NewAliasStore() *AliasStore {
+	return &AliasStore{aliases: make(map[string][]string)}
+}
+
+// load reads the alias file once.
+func (s *AliasStore) load() {
+	s.once.Do(func() {
+		s.path = filepath.Join(config.DataDir, "aliases.json")
+		if err := readJSONFile(s.path, &s.aliases); err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("load aliases: %v", err)
+			}
+			// 途中まで読めた内容は使わない
+			s.aliases = make(map[string][]string)
+		}
+	})
+}
+
+// save writes all aliases to disk. The caller must hold s.mu.
+func (s *AliasStore) save() error {
+	return writeJSONFile(s.path, s.aliases)
+}
+
+// Get returns the stored aliases of a video.
+func (s *AliasStore) Get(videoID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+	return append([]string(nil), s.aliases[videoID]...)
+}
+
+// List returns the aliases of every video that has any.
+func (s *AliasStore) List() map[string][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+	res := make(map[string][]string, len(s.aliases))
+	for id, a := range s.aliases {
+		res[id] = append([]string(nil), a...)
+	}
+	return res
+}
+
+// Set replaces the aliases of a video. Blank and duplicate entries are
+// dropped and an empty list removes the video from the store.
+func (s *AliasStore) Set(videoID string, aliases []string) ([]string, error) {
+	var clean []string
+	seen := make(map[string]bool)
+	for _, a := range aliases {
+		a = strings.TrimSpace(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		clean = append(clean, a)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.load()
+	if len(clean) == 0 {
+		delete(s.aliases, videoID)
+	} else {
+		s.aliases[videoID] = clean
+	}
+	return clean, s.save()
+}
+
+// acceptedAnswers returns the cleaned title followed by every alias of the
+// video, each Japanese answer also in its kana reading and romaji spelling.
+// The raw title is only kept for display, since its "MV" or "official music
+// video" decorations would otherwise count as the answer.
+func (s *AliasStore) acceptedAnswers(item VideoItem) []string {
+	answers := append([]string{item.Title}, item.Aliases...)
+	answers = append(answers, s.Get(item.ID)...)
+	if item.Reading != "" {
+		answers = append(answers, item.Reading)
+	}
+	seen := make(map[string]bool)
+	var res []string
+	for _, a := range answers {
+		for _, f := range append([]string{a}, readingForms(a)...) {
+			if !seen[f] {
+				seen[f] = true
+				res = append(res, f)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAliasStoreSet(t *testing.T) {
+	useDataDir(t)
+	s := NewAliasStore()
+	got, err := s.Set("vid1", []string{" Pretender ", "", "Pretender", "プリテンダー"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Pretender", "プリテンダー"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Set() = %q, want %q", got, want)
+	}
+	if got := NewAliasStore().Get("vid1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() after reload = %q, want %q", got, want)
+	}
+
+	if _, err := s.Set("vid1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := NewAliasStore().List(); len(got) != 0 {
+		t.Errorf("List() after clearing = %q, want empty", got)
+	}
+}
+
+func TestAliasStoreKeepsCorruptFile(t *testing.T) {
+	dir := useDataDir(t)
+	path := filepath.Join(dir, "aliases.json")
+	broken := []byte(`{"vid1":["Pretender",`)
+	if err := os.WriteFile(path, broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewAliasStore()
+	if got := s.List(); len(got) != 0 {
+		t.Errorf("List() = %q, want empty", got)
+	}
+	if _, err := s.Set("vid2", []string{"Lemon"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(path + ".corrupt"); err != nil || string(got) != string(broken) {
+		t.Errorf("corrupt file = %q, %v; want %q", got, err, broken)
+	}
+}
+
+func TestAcceptedAnswersIgnoreRawTitle(t *testing.T) {
+	useDataDir(t)
+	item := VideoItem{ID: "vid1", Title: "Pretender", RawTitle: "Official髭男dism - Pretender [Official Music Video]"}
+	accepted := NewAliasStore().acceptedAnswers(item)
+	for _, a := range accepted {
+		if a == item.RawTitle {
+			t.Fatalf("acceptedAnswers() = %q, includes the raw title", accepted)
+		}
+	}
+	judge := FuzzyJudge{Strictness: StrictnessLenient}
+	for _, answer := range []string{"MV", "official music video"} {
+		if judge.Judge(accepted, answer).Correct {
+			t.Errorf("Judge(%q) = correct, want the decoration rejected", answer)
+		}
+	}
+	if !judge.Judge(accepted, "pretender").Correct {
+		t.Error(`Judge("pretender") = incorrect, want correct`)
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// Reasons for rejecting an appeal or a vote.
+var (
+	ErrAppealClosed  = errors.New("no rejected answer to appeal")
+	ErrAppealPending = errors.New("another appeal is being voted on")
+	ErrNoVoters      = errors.New("no other players to vote")
+	ErrNoAppeal      = errors.New("no appeal to vote on")
+	ErrNotVoter      = errors.New("cannot vote on this appeal")
+)
+
+// rejectedAnswer is a wrong answer that can still be appealed.
+type rejectedAnswer struct {
+	User   string
+	Answer string
+	// Question and Record locate the answer in RoomState.Questions.
+	Question int
+	Record   int
+	VideoID  string
+	// Until is when the appeal window closes.
+	Until time.Time
+}
+
+// appeal is a rejected answer put to a vote of the other players.
+type appeal struct {
+	ID int
+	rejectedAnswer
+	// Votes holds each voter's decision; voters who have not voted are absent.
+	Votes    map[string]bool
+	Voters   map[string]bool
+	Deadline time.Time
+}
+
+// passed reports whether a majority of all voters accepts the appeal. Voters
+// who do not vote count against it.
+func (a *appeal) passed() (bool, int, int) {
+	accept := 0
+	for _, v := range a.Votes {
+		if v {
+			accept++
+		}
+	}
+	reject := len(a.Votes) - accept
+	return accept*2 > len(a.Voters), accept, reject
+}
+
+// recordRejection keeps the wrong answer of user open for an appeal. The
+// caller must hold the lock.
+func (st *RoomState) recordRejection(user, answer string) {
+	q := st.currentQuestion()
+	if q == nil || len(q.Answers) == 0 || st.Tiebreak != nil {
+		return
+	}
+	if st.Rejections == nil {
+		st.Rejections = make(map[string]rejectedAnswer)
+	}
+	st.Rejections[user] = rejectedAnswer{
+		User:     user,
+		Answer:   answer,
+		Question: len(st.Questions) - 1,
+		Record:   len(q.Answers) - 1,
+		VideoID:  q.VideoID,
+		Until:    time.Now().Add(time.Duration(config.AppealWindow) * time.Second),
+	}
+}
+
+// appealDeadline returns when the appeal window for the last wrong answer of
+// user closes, or the zero time when it cannot be appealed.
+func (m *RoomManager) appealDeadline(roomID, user string) time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil {
+		return time.Time{}
+	}
+	return st.Rejections[user].Until
+}
+
+// FileAppeal puts the last wrong answer of the player on conn to a vote of the
+// other players in the room and announces it. Appeals must be filed within the
+// appeal window and only one appeal is voted on at a time.
+func (m *RoomManager) FileAppeal(roomID string, conn *websocket.Conn) error {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st == nil {
+		m.mu.Unlock()
+		return ErrAppealClosed
+	}
+	user, joined := st.Users[conn]
+	if !joined {
+		m.mu.Unlock()
+		return ErrAppealClosed
+	}
+	rej, ok := st.Rejections[user]
+	if !ok || time.Now().After(rej.Until) || st.Phase == PhaseFinished {
+		m.mu.Unlock()
+		return ErrAppealClosed
+	}
+	if st.Appeal != nil {
+		m.mu.Unlock()
+		return ErrAppealPending
+	}
+	voters := make(map[string]bool)
+	for _, u := range st.Users {
+		if u != user {
+			voters[u] = true
+		}
+	}
+	if len(voters) == 0 {
+		m.mu.Unlock()
+		return ErrNoVoters
+	}
+	delete(st.Rejections, user)
+	st.AppealGen++
+	a := &appeal{
+		ID:             st.AppealGen,
+		rejectedAnswer: rej,
+		Votes:          make(map[string]bool),
+		Voters:         voters,
+		Deadline:       time.Now().Add(time.Duration(config.AppealWindow) * time.Second),
+	}
+	st.Appeal = a
+	m.mu.Unlock()
+
+	msg, _ := json.Marshal(&model.ServerMessage{Type: "appeal", User: user, Text: rej.Answer, Deadline: a.Deadline.UnixMilli(), Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, msg)
+	time.AfterFunc(time.Until(a.Deadline), func() {
+		m.resolveAppeal(roomID, st, a.ID)
+	})
+	return nil
+}
+
+// VoteAppeal records the vote of the player on conn on the current appeal. The
+// appeal is decided as soon as every other player has voted.
+func (m *RoomManager) VoteAppeal(roomID string, conn *websocket.Conn, accept bool) error {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st == nil || st.Appeal == nil {
+		m.mu.Unlock()
+		return ErrNoAppeal
+	}
+	a := st.Appeal
+	user, joined := st.Users[conn]
+	if !joined || !a.Voters[user] {
+		m.mu.Unlock()
+		return ErrNotVoter
+	}
+	a.Votes[user] = accept
+	done := len(a.Votes) == len(a.Voters)
+	m.mu.Unlock()
+
+	if done {
+		m.resolveAppeal(roomID, st, a.ID)
+	}
+	return nil
+}
+
+// appealRemaining returns how long the current appeal is still open for votes.
+func (m *RoomManager) appealRemaining(roomID string) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil || st.Appeal == nil {
+		return 0
+	}
+	return time.Until(st.Appeal.Deadline)
+}
+
+// resolveAppeal closes the appeal with the given ID in room state owner. The
+// room is checked as well as the ID because IDs start over when a room is
+// opened again under the same name. When it passes, the
+// answer is marked correct and the point is awarded retroactively. The result
+// and the corrected standings are broadcast either way.
+func (m *RoomManager) resolveAppeal(roomID string, owner *RoomState, id int) {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st != owner || st.Appeal == nil || st.Appeal.ID != id {
+		m.mu.Unlock()
+		return
+	}
+	a := st.Appeal
+	st.Appeal = nil
+	ok, accept, reject := a.passed()
+	// 投票中に再戦などで記録が入れ替わっていれば取り消さない
+	if ok && a.Question < len(st.Questions) && st.Questions[a.Question].VideoID == a.VideoID {
+		q := &st.Questions[a.Question]
+		if a.Record < len(q.Answers) {
+			q.Answers[a.Record].Correct = true
+			q.Answers[a.Record].Appealed = true
+		}
+		if q.AnsweredBy == "" {
+			q.AnsweredBy = a.User
+			q.Points = pointsPerCorrect
+		}
+		st.Scores[a.User] += pointsPerCorrect
+	} else {
+		ok = false
+	}
+	msg := &model.ServerMessage{
+		Type:      "appeal_result",
+		User:      a.User,
+		Text:      a.Answer,
+		Correct:   ok,
+		Votes:     map[string]int{"accept": accept, "reject": reject},
+		Scores:    copyScores(st.Scores),
+		Standings: standings(st.Scores, ""),
+		Timestamp: time.Now().UnixMilli(),
+	}
+	m.mu.Unlock()
+
+	resp, _ := json.Marshal(msg)
+	m.Broadcast(roomID, nil, websocket.TextMessage, resp)
+}
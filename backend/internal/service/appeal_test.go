@@ -0,0 +1,46 @@
+package service
+
+import "testing"
+
+func TestAppeal(t *testing.T) {
+	m, conns := testRoom(t, "r1", "alice", "bob", "carol")
+	st := m.states["r1"]
+	if _, err := m.NextVideo("r1"); err != nil {
+		t.Fatal(err)
+	}
+	m.StartQuestion("r1")
+	m.AddBuzz("r1", "bob")
+	if verdict, _ := m.SubmitAnswer("r1", "bob", "almost right"); verdict.Correct {
+		t.Fatal("SubmitAnswer() accepted the wrong answer")
+	}
+
+	if err := m.FileAppeal("r1", conns[2]); err != ErrAppealClosed {
+		t.Errorf("FileAppeal() by carol = %v, want ErrAppealClosed", err)
+	}
+	if err := m.FileAppeal("r1", conns[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.FileAppeal("r1", conns[1]); err != ErrAppealClosed {
+		t.Errorf("second FileAppeal() = %v, want ErrAppealClosed", err)
+	}
+	if err := m.VoteAppeal("r1", conns[1], true); err != ErrNotVoter {
+		t.Errorf("VoteAppeal() by bob = %v, want ErrNotVoter", err)
+	}
+
+	if err := m.VoteAppeal("r1", conns[0], true); err != nil {
+		t.Fatal(err)
+	}
+	if st.Appeal == nil {
+		t.Fatal("appeal decided before every voter voted")
+	}
+	if err := m.VoteAppeal("r1", conns[2], true); err != nil {
+		t.Fatal(err)
+	}
+	q := st.Questions[0]
+	if st.Appeal != nil || st.Scores["bob"] != 1 || q.AnsweredBy != "bob" || !q.Answers[0].Correct || !q.Answers[0].Appealed {
+		t.Errorf("after a passed appeal: scores %v, question %+v", st.Scores, q)
+	}
+	if err := m.VoteAppeal("r1", conns[0], true); err != ErrNoAppeal {
+		t.Errorf("VoteAppeal() after the result = %v, want ErrNoAppeal", err)
+	}
+}
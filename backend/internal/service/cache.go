@@ -0,0 +1,200 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// playlistEntry is a cached playlist with the time it expires.
+type playlistEntry struct {
+	Videos  []VideoItem `json:"videos"`
+	Expires time.Time   `json:"expires"`
+}
+
+// videoEntry is the cached status of a video.
+type videoEntry struct {
+	VideoStatus
+	Expires time.Time `json:"expires"`
+}
+
+// cacheFile is the on-disk form of the cache.
+type cacheFile struct {
+	Playlists map[string]playlistEntry `json:"playlists"`
+	Videos    map[string]videoEntry    `json:"videos"`
+}
+
+// CacheStats reports the size and hit counts of the YouTube cache.
+type CacheStats struct {
+	Playlists      int   `json:"playlists"`
+	Videos         int   `json:"videos"`
+	PlaylistHits   int64 `json:"playlistHits"`
+	PlaylistMisses int64 `json:"playlistMisses"`
+	VideoHits      int64 `json:"videoHits"`
+	VideoMisses    int64 `json:"videoMisses"`
+}
+
+// YouTubeCache keeps playlist contents and video status fetched from the
+// YouTube API so that rooms picking the same playlist share one download.
+// Entries expire after the configured TTLs and the oldest entries are evicted
+// once the size limits are reached. When config.CachePersist is set the cache
+// is kept in the data directory across restarts.
+type YouTubeCache struct {
+	mu    sync.Mutex
+	once  sync.Once
+	path  string
+	data  cacheFile
+	stats CacheStats
+}
+
+// NewYouTubeCache creates a YouTubeCache. Persisted entries are loaded on first use.
+func NewYouTubeCache() *YouTubeCache {
+	return &YouTubeCache{data: cacheFile{
+		Playlists: make(map[string]playlistEntry),
+		Videos:    make(map[string]videoEntry),
+	}}
+}
+
+// load reads the persisted cache once, dropping expired entries.
+func (c *YouTubeCache) load() {
+	c.once.Do(func() {
+		if !config.CachePersist {
+			return
+		}
+		c.path = filepath.Join(config.DataDir, "youtube_cache.json")
+		data, err := os.ReadFile(c.path)
+		if err != nil {
+			return
+		}
+		var f cacheFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			log.Printf("load youtube cache: %v", err)
+			return
+		}
+		now := time.Now()
+		for id, e := range f.Playlists {
+			if now.Before(e.Expires) {
+				c.data.Playlists[id] = e
+			}
+		}
+		for id, e := range f.Videos {
+			if now.Before(e.Expires) {
+				c.data.Videos[id] = e
+			}
+		}
+	})
+}
+
+// save writes the cache to disk when persistence is enabled. The caller must hold c.mu.
+func (c *YouTubeCache) save() {
+	if c.path == "" {
+		return
+	}
+	if err := writeJSONFile(c.path, c.data); err != nil {
+		log.Printf("save youtube cache: %v", err)
+	}
+}
+
+// Playlist returns a copy of the cached videos of a playlist.
+func (c *YouTubeCache) Playlist(playlistID string) ([]VideoItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	e, ok := c.data.Playlists[playlistID]
+	if !ok || time.Now().After(e.Expires) {
+		c.stats.PlaylistMisses++
+		return nil, false
+	}
+	c.stats.PlaylistHits++
+	return append([]VideoItem(nil), e.Videos...), true
+}
+
+// PutPlaylist caches the videos of a playlist.
+func (c *YouTubeCache) PutPlaylist(playlistID string, videos []VideoItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	c.data.Playlists[playlistID] = playlistEntry{
+		Videos:  append([]VideoItem(nil), videos...),
+		Expires: time.Now().Add(time.Duration(config.CachePlaylistTTL) * time.Second),
+	}
+	c.evictPlaylists()
+	c.save()
+}
+
+// Statuses returns the cached status of the videos and the IDs that are not cached.
+func (c *YouTubeCache) Statuses(videoIDs []string) (map[string]VideoStatus, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	now := time.Now()
+	statuses := make(map[string]VideoStatus, len(videoIDs))
+	var missing []string
+	for _, id := range videoIDs {
+		if e, ok := c.data.Videos[id]; ok && now.Before(e.Expires) {
+			statuses[id] = e.VideoStatus
+			c.stats.VideoHits++
+			continue
+		}
+		missing = append(missing, id)
+		c.stats.VideoMisses++
+	}
+	return statuses, missing
+}
+
+// PutStatuses caches the status of videos.
+func (c *YouTubeCache) PutStatuses(statuses map[string]VideoStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	expires := time.Now().Add(time.Duration(config.CacheVideoTTL) * time.Second)
+	for id, s := range statuses {
+		c.data.Videos[id] = videoEntry{VideoStatus: s, Expires: expires}
+	}
+	c.evictVideos()
+	c.save()
+}
+
+// Stats returns the current cache size and hit counts.
+func (c *YouTubeCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	s := c.stats
+	s.Playlists = len(c.data.Playlists)
+	s.Videos = len(c.data.Videos)
+	return s
+}
+
+// evictPlaylists removes the playlists expiring first until the size limit is
+// met. The caller must hold c.mu.
+func (c *YouTubeCache) evictPlaylists() {
+	for len(c.data.Playlists) > config.CacheMaxPlaylists {
+		oldest := ""
+		for id, e := range c.data.Playlists {
+			if oldest == "" || e.Expires.Before(c.data.Playlists[oldest].Expires) {
+				oldest = id
+			}
+		}
+		delete(c.data.Playlists, oldest)
+	}
+}
+
+// evictVideos removes the video entries expiring first until the size limit is
+// met. The caller must hold c.mu.
+func (c *YouTubeCache) evictVideos() {
+	for len(c.data.Videos) > config.CacheMaxVideos {
+		oldest := ""
+		for id, e := range c.data.Videos {
+			if oldest == "" || e.Expires.Before(c.data.Videos[oldest].Expires) {
+				oldest = id
+			}
+		}
+		delete(c.data.Videos, oldest)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// ErrChallengeNotFound is returned when a challenge code is unknown.
+var ErrChallengeNotFound = errors.New("challenge not found")
+
+// challengeAlphabet avoids characters that are easy to confuse when sharing codes.
+const challengeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// challengeCodeLength is the number of characters in a challenge code.
+const challengeCodeLength = 6
+
+// RunQuestion is one question of a recorded solo run.
+type RunQuestion struct {
+	VideoID    string   `json:"videoId"`
+	VideoTitle string   `json:"videoTitle"`
+	RawTitle   string   `json:"rawTitle,omitempty"`
+	Channel    string   `json:"channel,omitempty"`
+	Start      int      `json:"start"`
+	Pack       string   `json:"pack,omitempty"`
+	Aliases    []string `json:"aliases,omitempty"`
+	Reading    string   `json:"reading,omitempty"`
+	Answers    []string `json:"answers,omitempty"`
+	Correct    bool     `json:"correct"`
+	ReactionMs int64    `json:"reactionMs,omitempty"`
+	Outcome    string   `json:"outcome"`
+}
+
+// ChallengeRun is a recorded solo run that others can replay by its code.
+type ChallengeRun struct {
+	Code       string        `json:"code"`
+	User       string        `json:"user"`
+	PlaylistID string        `json:"playlistId,omitempty"`
+	Questions  []RunQuestion `json:"questions"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+// ChallengeSummary is the public description of a challenge. It leaves out
+// the tracks and answers so the run cannot be looked up before it is played.
+type ChallengeSummary struct {
+	Code       string    `json:"code"`
+	User       string    `json:"user"`
+	PlaylistID string    `json:"playlistId,omitempty"`
+	Questions  int       `json:"questions"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Summary returns the public description of the run.
+func (r *ChallengeRun) Summary() ChallengeSummary {
+	return ChallengeSummary{
+		Code:       r.Code,
+		User:       r.User,
+		PlaylistID: r.PlaylistID,
+		Questions:  len(r.Questions),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// videos returns the run's track sequence with its clip offsets.
+func (r *ChallengeRun) videos() []VideoItem {
+	list := make([]VideoItem, 0, len(r.Questions))
+	for _, q := range r.Questions {
+		list = append(list, VideoItem{ID: q.VideoID, Title: q.VideoTitle, RawTitle: q.RawTitle, Channel: q.Channel, Start: q.Start, Aliases: q.Aliases, Reading: q.Reading, Pack: q.Pack})
+	}
+	return list
+}
+
+// ChallengeStore persists challenge runs as JSON files in the data directory.
+type ChallengeStore struct {
+	mu sync.RWMutex
+}
+
+// NewChallengeStore creates a ChallengeStore.
+func NewChallengeStore() *ChallengeStore {
+	return &ChallengeStore{}
+}
+
+// dir returns the directory holding challenge files.
+func (c *ChallengeStore) dir() string {
+	return filepath.Join(config.DataDir, "challenges")
+}
+
+// newChallengeCode returns a random shareable code.
+func newChallengeCode() (string, error) {
+	b := make([]byte, challengeCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = challengeAlphabet[int(b[i])%len(challengeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeChallengeCode upper-cases a code and reports whether it is well formed.
+func normalizeChallengeCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != challengeCodeLength || strings.Trim(code, challengeAlphabet) != "" {
+		return "", false
+	}
+	return code, true
+}
+
+// Save stores a run under a new code and returns it.
+func (c *ChallengeStore) Save(run *ChallengeRun) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		code, err := newChallengeCode()
+		if err != nil {
+			return "", err
+		}
+		path := filepath.Join(c.dir(), code+".json")
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		run.Code = code
+		return code, writeJSONFile(path, run)
+	}
+}
+
+// Get loads a run by its code.
+func (c *ChallengeStore) Get(code string) (*ChallengeRun, error) {
+	code, ok := normalizeChallengeCode(code)
+	if !ok {
+		return nil, ErrChallengeNotFound
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data, err := os.ReadFile(filepath.Join(c.dir(), code+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var run ChallengeRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// compareRuns lines up a replay against the original run question by question.
+func compareRuns(original *ChallengeRun, user string, replay []RunQuestion) *model.ChallengeComparison {
+	cmp := &model.ChallengeComparison{Code: original.Code, Original: original.User, Challenger: user}
+	played := make(map[string]RunQuestion, len(replay))
+	for _, q := range replay {
+		played[q.VideoID] = q
+	}
+	for i, q := range original.Questions {
+		row := model.ComparisonRow{
+			Round:      i + 1,
+			VideoID:    q.VideoID,
+			VideoTitle: q.VideoTitle,
+			Original:   model.RunResult{Correct: q.Correct, ReactionMs: q.ReactionMs, Outcome: q.Outcome},
+		}
+		if q.Correct {
+			cmp.OriginalCorrect++
+		}
+		if r, ok := played[q.VideoID]; ok {
+			row.Challenger = model.RunResult{Correct: r.Correct, ReactionMs: r.ReactionMs, Outcome: r.Outcome}
+			if r.Correct {
+				cmp.ChallengerCorrect++
+			}
+		}
+		cmp.Rows = append(cmp.Rows, row)
+	}
+	return cmp
+}
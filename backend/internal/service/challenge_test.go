@@ -0,0 +1,38 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChallengeStore(t *testing.T) {
+	useDataDir(t)
+	c := NewChallengeStore()
+	run := &ChallengeRun{User: "alice", PlaylistID: "PL1", Questions: []RunQuestion{
+		{VideoID: "v1", VideoTitle: "Pretender", Start: 30, Correct: true, Outcome: OutcomeCorrect},
+		{VideoID: "v2", VideoTitle: "Lemon", Outcome: OutcomeTimeout},
+	}}
+	code, err := c.Save(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := normalizeChallengeCode(code); !ok || run.Code != code {
+		t.Fatalf("Save() = %q, run code %q", code, run.Code)
+	}
+
+	got, err := c.Get(" " + strings.ToLower(code) + " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.User != "alice" || len(got.Questions) != 2 || got.Questions[0].Start != 30 {
+		t.Errorf("Get() = %+v", got)
+	}
+	if videos := got.videos(); len(videos) != 2 || videos[1].ID != "v2" || videos[0].Start != 30 {
+		t.Errorf("videos() = %+v, want the recorded order and offsets", videos)
+	}
+	for _, bad := range []string{"../x", "ABCDE1", "ZZZZZZ"} {
+		if _, err := c.Get(bad); err != ErrChallengeNotFound {
+			t.Errorf("Get(%q) = %v, want ErrChallengeNotFound", bad, err)
+		}
+	}
+}
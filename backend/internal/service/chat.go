@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// Reasons for rejecting a chat message.
+var (
+	ErrChatEmpty       = errors.New("message is empty")
+	ErrChatTooLong     = errors.New("message is too long")
+	ErrChatRateLimited = errors.New("sending messages too fast")
+	ErrChatDuringQuiz  = errors.New("chat is disabled during a question")
+	ErrChatNotJoined   = errors.New("join the room before chatting")
+)
+
+// questionInProgress reports whether a question is being played and not yet resolved.
+func (st *RoomState) questionInProgress() bool {
+	return st.Phase == PhasePlaying && (st.Active || st.Fastest != "" || len(st.BuzzOrder) > 0)
+}
+
+// sanitizeChat trims the text and drops control characters.
+func sanitizeChat(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
+
+// PostChat validates a chat message sent on conn and returns the sender's
+// registered name and the text to relay. Messages are rejected from connections
+// that have not joined, while a question is in progress, when they exceed the
+// configured length or when the sender exceeds the rate limit.
+func (m *RoomManager) PostChat(roomID string, conn *websocket.Conn, text string) (string, string, error) {
+	text = sanitizeChat(text)
+	if text == "" {
+		return "", "", ErrChatEmpty
+	}
+	if utf8.RuneCountInString(text) > config.ChatMaxLength {
+		return "", "", ErrChatTooLong
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.states[roomID]
+	if st == nil {
+		return "", "", ErrChatNotJoined
+	}
+	// 名前はクライアント申告ではなく join 時に登録したものを使う
+	user, ok := st.Users[conn]
+	if !ok {
+		return "", "", ErrChatNotJoined
+	}
+	if st.questionInProgress() {
+		return "", "", ErrChatDuringQuiz
+	}
+	now := time.Now()
+	window := time.Duration(config.ChatRateWindow) * time.Second
+	recent := st.ChatLog[user][:0]
+	for _, t := range st.ChatLog[user] {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= config.ChatRateLimit {
+		st.ChatLog[user] = recent
+		return "", "", ErrChatRateLimited
+	}
+	st.ChatLog[user] = append(recent, now)
+	return user, text, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// ErrDailyNotConfigured is returned when no daily playlist has been configured.
+var ErrDailyNotConfigured = errors.New("daily playlist not configured")
+
+// ErrDailyPlaylistFixed is returned when a daily room tries to change its playlist.
+var ErrDailyPlaylistFixed = errors.New("the daily challenge playlist cannot be changed")
+
+// jst is the time zone the daily challenge resets in.
+var jst = time.FixedZone("JST", 9*60*60)
+
+// IsDailyRoom reports whether the room plays the daily challenge.
+func IsDailyRoom(roomID string) bool {
+	return roomID == "daily" || strings.HasPrefix(roomID, "daily-")
+}
+
+// DailyDate returns the daily challenge date for t in JST.
+func DailyDate(t time.Time) string {
+	return t.In(jst).Format("2006-01-02")
+}
+
+// DailyEntry is the result of a player's first game in a daily challenge.
+type DailyEntry struct {
+	User       string    `json:"user"`
+	Score      int       `json:"score"`
+	Rank       int       `json:"rank"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// DailyLeaderboard is the ranking of a single day.
+type DailyLeaderboard struct {
+	Date       string       `json:"date"`
+	PlaylistID string       `json:"playlistId"`
+	Entries    []DailyEntry `json:"entries"`
+}
+
+// DailyService derives the daily track list and keeps the daily leaderboard.
+type DailyService struct {
+	mu     sync.Mutex
+	once   sync.Once
+	sets   map[string][]VideoItem
+	board  DailyLeaderboard
+	source *VideoSource
+}
+
+// NewDailyService creates a DailyService.
+func NewDailyService(source *VideoSource) *DailyService {
+	return &DailyService{sets: make(map[string][]VideoItem), source: source}
+}
+
+// path returns the file the leaderboard is persisted to.
+func (d *DailyService) path() string {
+	return filepath.Join(config.DataDir, "daily.json")
+}
+
+// load reads the persisted leaderboard once. The caller must hold d.mu.
+func (d *DailyService) load() {
+	d.once.Do(func() {
+		if err := readJSONFile(d.path(), &d.board); err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("load daily leaderboard: %v", err)
+			}
+			// 途中まで読めた内容は使わず rollover で作り直す
+			d.board = DailyLeaderboard{}
+		}
+	})
+}
+
+// save writes the leaderboard to disk. The caller must hold d.mu.
+func (d *DailyService) save() error {
+	return writeJSONFile(d.path(), d.board)
+}
+
+// rollover clears the leaderboard when the JST date has changed. The caller must hold d.mu.
+func (d *DailyService) rollover() {
+	today := DailyDate(time.Now())
+	if d.board.Date != today {
+		d.board = DailyLeaderboard{Date: today, PlaylistID: config.DailyPlaylistID}
+	}
+	for date := range d.sets {
+		if date != today {
+			delete(d.sets, date)
+		}
+	}
+}
+
+// dailySeed derives the random seed for a date and playlist.
+func dailySeed(date, playlistID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(date + ":" + playlistID))
+	return int64(h.Sum64())
+}
+
+// Videos returns the track list of the daily challenge for date. Every call for
+// the same date and playlist yields the same order and clip offsets.
+func (d *DailyService) Videos(date string) ([]VideoItem, error) {
+	if config.DailyPlaylistID == "" {
+		return nil, ErrDailyNotConfigured
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if set, ok := d.sets[date]; ok {
+		return append([]VideoItem(nil), set...), nil
+	}
+	videos, err := d.source.Videos(context.Background(), config.DailyPlaylistID)
+	if err != nil {
+		return nil, err
+	}
+	// プレイリストの並び順に依存しないよう ID でソートしてからシャッフルする
+	sort.Slice(videos, func(i, j int) bool { return videos[i].ID < videos[j].ID })
+	rng := rand.New(rand.NewSource(dailySeed(date, config.DailyPlaylistID)))
+	rng.Shuffle(len(videos), func(i, j int) { videos[i], videos[j] = videos[j], videos[i] })
+	for i := range videos {
+		videos[i].Start = rng.Intn(config.DailyMaxOffset + 1)
+	}
+	d.sets[date] = videos
+	return append([]VideoItem(nil), videos...), nil
+}
+
+// Record adds the final standings of a daily game to the leaderboard. Only the
+// first finished game of each player counts, since a rematch replays the same
+// tracks in the same order, and results of a past day are ignored.
+func (d *DailyService) Record(date string, final []model.Standing) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.load()
+	d.rollover()
+	if date != d.board.Date {
+		return
+	}
+	now := time.Now()
+	for _, s := range final {
+		found := false
+		for _, e := range d.board.Entries {
+			if e.User == s.User {
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.board.Entries = append(d.board.Entries, DailyEntry{User: s.User, Score: s.Score, FinishedAt: now})
+		}
+	}
+	sort.SliceStable(d.board.Entries, func(i, j int) bool {
+		a, b := d.board.Entries[i], d.board.Entries[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.FinishedAt.Before(b.FinishedAt)
+	})
+	for i := range d.board.Entries {
+		if i > 0 && d.board.Entries[i].Score == d.board.Entries[i-1].Score {
+			d.board.Entries[i].Rank = d.board.Entries[i-1].Rank
+		} else {
+			d.board.Entries[i].Rank = i + 1
+		}
+	}
+	if err := d.save(); err != nil {
+		log.Printf("save daily leaderboard: %v", err)
+	}
+}
+
+// Leaderboard returns today's leaderboard.
+func (d *DailyService) Leaderboard() DailyLeaderboard {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.load()
+	d.rollover()
+	board := d.board
+	board.Entries = append([]DailyEntry{}, d.board.Entries...)
+	return board
+}
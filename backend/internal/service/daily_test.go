@@ -0,0 +1,59 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"intro-quiz/backend/internal/model"
+)
+
+func TestDailyServiceKeepsCorruptFile(t *testing.T) {
+	dir := useDataDir(t)
+	path := filepath.Join(dir, "daily.json")
+	broken := []byte(`{"date":"2026-10-19","entries":[`)
+	if err := os.WriteFile(path, broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	today := DailyDate(time.Now())
+	d := NewDailyService(nil)
+	d.Record(today, []model.Standing{{User: "alice", Score: 2, Rank: 1}})
+
+	board := NewDailyService(nil).Leaderboard()
+	if board.Date != today || len(board.Entries) != 1 || board.Entries[0].User != "alice" {
+		t.Errorf("Leaderboard() after reload = %+v", board)
+	}
+	if got, err := os.ReadFile(path + ".corrupt"); err != nil || string(got) != string(broken) {
+		t.Errorf("corrupt file = %q, %v; want %q", got, err, broken)
+	}
+}
+
+func TestDailyServiceRecordsFirstGame(t *testing.T) {
+	useDataDir(t)
+	today := DailyDate(time.Now())
+	d := NewDailyService(nil)
+	d.Record(today, []model.Standing{{User: "alice", Score: 1, Rank: 1}})
+	// 同じ日のリマッチでは既知の出題順で良い点が取れても記録しない
+	d.Record(today, []model.Standing{
+		{User: "alice", Score: 5, Rank: 1},
+		{User: "bob", Score: 3, Rank: 2},
+	})
+	d.Record("2000-01-01", []model.Standing{{User: "carol", Score: 9, Rank: 1}})
+
+	want := []struct {
+		user  string
+		score int
+		rank  int
+	}{{"bob", 3, 1}, {"alice", 1, 2}}
+	got := d.Leaderboard().Entries
+	if len(got) != len(want) {
+		t.Fatalf("Leaderboard().Entries = %+v, want %d entries", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].User != w.user || got[i].Score != w.score || got[i].Rank != w.rank {
+			t.Errorf("entry %d = %+v, want %s %d rank %d", i, got[i], w.user, w.score, w.rank)
+		}
+	}
+}
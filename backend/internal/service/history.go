@@ -0,0 +1,184 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// ErrMatchNotFound is returned when a match ID is unknown.
+var ErrMatchNotFound = errors.New("match not found")
+
+// AnswerRecord is a single answer submitted during a question.
+type AnswerRecord struct {
+	User       string  `json:"user"`
+	Answer     string  `json:"answer"`
+	Correct    bool    `json:"correct"`
+	Matched    string  `json:"matched,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Appealed   bool    `json:"appealed,omitempty"`
+}
+
+// QuestionRecord describes what happened during one question of a match.
+type QuestionRecord struct {
+	Round      int            `json:"round"`
+	VideoID    string         `json:"videoId"`
+	VideoTitle string         `json:"videoTitle"`
+	RawTitle   string         `json:"rawTitle,omitempty"`
+	Channel    string         `json:"channel,omitempty"`
+	BuzzOrder  []string       `json:"buzzOrder,omitempty"`
+	Answers    []AnswerRecord `json:"answers,omitempty"`
+	AnsweredBy string         `json:"answeredBy,omitempty"`
+	ReactionMs int64          `json:"reactionMs,omitempty"`
+	Points     int            `json:"points,omitempty"`
+	Outcome    string         `json:"outcome,omitempty"`
+}
+
+// MatchRecord is a finished game as stored in the match history.
+type MatchRecord struct {
+	ID         string                `json:"id"`
+	RoomID     string                `json:"roomId"`
+	PlaylistID string                `json:"playlistId"`
+	Players    []string              `json:"players"`
+	Questions  []QuestionRecord      `json:"questions"`
+	Standings  []model.Standing      `json:"standings"`
+	Tiebreak   *model.TiebreakResult `json:"tiebreak,omitempty"`
+	StartedAt  time.Time             `json:"startedAt"`
+	EndedAt    time.Time             `json:"endedAt"`
+}
+
+// MatchSummary is the short form of a match used in listings.
+type MatchSummary struct {
+	ID         string    `json:"id"`
+	RoomID     string    `json:"roomId"`
+	PlaylistID string    `json:"playlistId"`
+	Players    []string  `json:"players"`
+	Winners    []string  `json:"winners"`
+	Questions  int       `json:"questions"`
+	EndedAt    time.Time `json:"endedAt"`
+}
+
+// HistoryStore persists finished matches as JSON files in the data directory.
+type HistoryStore struct {
+	mu sync.RWMutex
+}
+
+// NewHistoryStore creates a HistoryStore.
+func NewHistoryStore() *HistoryStore {
+	return &HistoryStore{}
+}
+
+// dir returns the directory holding match files.
+func (h *HistoryStore) dir() string {
+	return filepath.Join(config.DataDir, "matches")
+}
+
+// newMatchID returns a random identifier for a match.
+func newMatchID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// validMatchID reports whether id is safe to use as a file name.
+func validMatchID(id string) bool {
+	if id == "" {
+		return false
+	}
+	return strings.Trim(id, "0123456789abcdef") == ""
+}
+
+// Save stores a finished match and assigns it an ID if it has none.
+func (h *HistoryStore) Save(rec *MatchRecord) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if rec.ID == "" {
+		rec.ID = newMatchID()
+	}
+	return writeJSONFile(filepath.Join(h.dir(), rec.ID+".json"), rec)
+}
+
+// Get loads a single match by ID.
+func (h *HistoryStore) Get(id string) (*MatchRecord, error) {
+	if !validMatchID(id) {
+		return nil, ErrMatchNotFound
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	data, err := os.ReadFile(filepath.Join(h.dir(), id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec MatchRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// List returns summaries of all stored matches, newest first.
+func (h *HistoryStore) List() ([]MatchSummary, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	entries, err := os.ReadDir(h.dir())
+	if errors.Is(err, os.ErrNotExist) {
+		return []MatchSummary{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := make([]MatchSummary, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(h.dir(), e.Name()))
+		if err != nil {
+			continue
+		}
+		var rec MatchRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			continue
+		}
+		list = append(list, rec.Summary())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].EndedAt.After(list[j].EndedAt)
+	})
+	return list, nil
+}
+
+// Summary returns the short form of the match.
+func (r *MatchRecord) Summary() MatchSummary {
+	var winners []string
+	for _, s := range r.Standings {
+		if s.Rank == 1 {
+			winners = append(winners, s.User)
+		}
+	}
+	return MatchSummary{
+		ID:         r.ID,
+		RoomID:     r.RoomID,
+		PlaylistID: r.PlaylistID,
+		Players:    r.Players,
+		Winners:    winners,
+		Questions:  len(r.Questions),
+		EndedAt:    r.EndedAt,
+	}
+}
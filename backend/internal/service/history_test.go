@@ -0,0 +1,54 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"intro-quiz/backend/internal/model"
+)
+
+func TestHistoryStore(t *testing.T) {
+	dir := useDataDir(t)
+	h := NewHistoryStore()
+	now := time.Now()
+	older := &MatchRecord{RoomID: "r1", Players: []string{"alice", "bob"}, EndedAt: now.Add(-time.Hour),
+		Standings: []model.Standing{{User: "alice", Score: 2, Rank: 1}, {User: "bob", Score: 2, Rank: 1}}}
+	newer := &MatchRecord{RoomID: "r2", Players: []string{"carol"}, EndedAt: now,
+		Questions: []QuestionRecord{{Round: 1, VideoID: "v1"}},
+		Standings: []model.Standing{{User: "carol", Score: 1, Rank: 1}}}
+	for _, rec := range []*MatchRecord{older, newer} {
+		if err := h.Save(rec); err != nil {
+			t.Fatal(err)
+		}
+		if !validMatchID(rec.ID) {
+			t.Fatalf("Save() assigned invalid ID %q", rec.ID)
+		}
+	}
+	// 壊れたファイルは一覧から外す
+	if err := os.WriteFile(filepath.Join(dir, "matches", "0badf00d.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := h.Get(newer.ID)
+	if err != nil || got.RoomID != "r2" || len(got.Questions) != 1 {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+	for _, id := range []string{"../ratings", "deadbeef"} {
+		if _, err := h.Get(id); err != ErrMatchNotFound {
+			t.Errorf("Get(%q) = %v, want ErrMatchNotFound", id, err)
+		}
+	}
+
+	list, err := h.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != newer.ID || list[1].ID != older.ID {
+		t.Fatalf("List() = %+v, want the two matches newest first", list)
+	}
+	if len(list[1].Winners) != 2 || list[0].Questions != 1 {
+		t.Errorf("List() summaries = %+v", list)
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// readJSONFile decodes the JSON file at path into v. A file that cannot be
+// decoded is renamed to path+".corrupt" so that the next save does not
+// overwrite the data that could still be recovered by hand.
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		if rerr := os.Rename(path, path+".corrupt"); rerr != nil {
+			return fmt.Errorf("%w (keeping file aside failed: %v)", err, rerr)
+		}
+		return fmt.Errorf("%w (moved to %s.corrupt)", err, path)
+	}
+	return nil
+}
+
+// writeJSONFile writes v as indented JSON to path, creating the directory if
+// needed. The file is written to a temporary name and renamed into place so
+// readers never see a partial file.
+func writeJSONFile(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"intro-quiz/backend/internal/model"
+)
+
+// Names of the built-in answer judges.
+const (
+	JudgeContains   = "contains"
+	JudgeExact      = "exact"
+	JudgeFuzzy      = "fuzzy"
+	JudgeNormalized = "normalized"
+)
+
+// ErrInvalidJudge is returned for an unknown judge name.
+var ErrInvalidJudge = errors.New("invalid judge")
+
+// Verdict is the outcome of judging an answer.
+type Verdict struct {
+	Correct bool
+	// Confidence is how closely the answer matched, between 0 and 1.
+	Confidence float64
+	// Matched is the accepted answer (title, alias or reading) that matched.
+	Matched string
+}
+
+// AnswerJudge decides whether an answer matches any of the accepted answers of a question.
+type AnswerJudge interface {
+	Judge(accepted []string, answer string) Verdict
+}
+
+// judgeFactories creates a judge for the room settings, keyed by judge name.
+var judgeFactories = map[string]func(model.RoomSettings) AnswerJudge{
+	JudgeContains:   func(model.RoomSettings) AnswerJudge { return ContainsJudge{} },
+	JudgeExact:      func(model.RoomSettings) AnswerJudge { return ExactJudge{} },
+	JudgeNormalized: func(model.RoomSettings) AnswerJudge { return NormalizedJudge{} },
+	JudgeFuzzy: func(s model.RoomSettings) AnswerJudge {
+		return FuzzyJudge{Strictness: s.Strictness}
+	},
+}
+
+// RegisterJudge makes a judge selectable by name in the room settings. It must
+// be called before the server starts handling rooms.
+func RegisterJudge(name string, factory func(model.RoomSettings) AnswerJudge) {
+	judgeFactories[name] = factory
+}
+
+// ValidJudge reports whether name is a registered judge. Empty means the default.
+func ValidJudge(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, ok := judgeFactories[name]
+	return ok
+}
+
+// judgeFor returns the judge selected in the settings, falling back to fuzzy.
+func judgeFor(settings model.RoomSettings) AnswerJudge {
+	if f, ok := judgeFactories[settings.Judge]; ok {
+		return f(settings)
+	}
+	return judgeFactories[JudgeFuzzy](settings)
+}
+
+// bestVerdict scores every accepted answer and keeps the best match.
+func bestVerdict(accepted []string, score func(string) float64) Verdict {
+	var v Verdict
+	for _, a := range accepted {
+		if s := score(a); s > v.Confidence {
+			v = Verdict{Correct: true, Confidence: s, Matched: a}
+		}
+	}
+	return v
+}
+
+// ContainsJudge accepts answers contained in a title, ignoring case.
+type ContainsJudge struct{}
+
+// Judge implements AnswerJudge.
+func (ContainsJudge) Judge(accepted []string, answer string) Verdict {
+	a := strings.ToLower(strings.TrimSpace(answer))
+	return bestVerdict(accepted, func(t string) float64 {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if a == "" || !strings.Contains(t, a) {
+			return 0
+		}
+		return float64(utf8.RuneCountInString(a)) / float64(utf8.RuneCountInString(t))
+	})
+}
+
+// ExactJudge accepts answers equal to a title, ignoring case and surrounding spaces.
+type ExactJudge struct{}
+
+// Judge implements AnswerJudge.
+func (ExactJudge) Judge(accepted []string, answer string) Verdict {
+	a := strings.TrimSpace(answer)
+	return bestVerdict(accepted, func(t string) float64 {
+		if a == "" || !strings.EqualFold(strings.TrimSpace(t), a) {
+			return 0
+		}
+		return 1
+	})
+}
+
+// NormalizedJudge accepts answers equal to a title after normalizeAnswer,
+// ignoring spaces.
+type NormalizedJudge struct{}
+
+// Judge implements AnswerJudge.
+func (NormalizedJudge) Judge(accepted []string, answer string) Verdict {
+	a := strings.ReplaceAll(normalizeAnswer(answer), " ", "")
+	return bestVerdict(accepted, func(t string) float64 {
+		if a == "" || strings.ReplaceAll(normalizeAnswer(t), " ", "") != a {
+			return 0
+		}
+		return 1
+	})
+}
+
+// FuzzyJudge scores answers with typo tolerance and word-boundary matching at
+// the given strictness.
+type FuzzyJudge struct {
+	Strictness string
+}
+
+// Judge implements AnswerJudge.
+func (j FuzzyJudge) Judge(accepted []string, answer string) Verdict {
+	return bestVerdict(accepted, func(t string) float64 {
+		return scoreAnswer(t, answer, j.Strictness)
+	})
+}
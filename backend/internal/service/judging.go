@@ -0,0 +1,123 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// ErrNoJudgement is returned when no answer is waiting for the host's decision.
+var ErrNoJudgement = errors.New("no answer to judge")
+
+// pendingJudgement is a typed answer waiting for the host to accept or reject it.
+type pendingJudgement struct {
+	ID     int
+	User   string
+	Answer string
+	// Auto is the automatic verdict, used when the host does not decide in time.
+	Auto Verdict
+}
+
+// requestJudgement forwards the answer to the host as a "judge_request" when the
+// room uses host judging and starts the fallback timer. It reports false when the
+// answer should be judged automatically instead: host judging is off, the host
+// is the one answering or has left the room.
+func (m *RoomManager) requestJudgement(roomID, user, answer string) bool {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st == nil || !st.Settings.HostJudging || st.Host == "" || st.Host == user {
+		m.mu.Unlock()
+		return false
+	}
+	if st.Judgement != nil {
+		// 判定待ちの間に届いた回答は無視する
+		m.mu.Unlock()
+		return true
+	}
+	st.JudgementGen++
+	p := &pendingJudgement{
+		ID:     st.JudgementGen,
+		User:   user,
+		Answer: answer,
+		Auto:   judgeFor(st.Settings).Judge(st.acceptedAnswers(), answer),
+	}
+	st.Judgement = p
+	host := st.Host
+	deadline := time.Now().Add(time.Duration(config.JudgeTimeout) * time.Second)
+	req := &model.ServerMessage{
+		Type:         "judge_request",
+		User:         user,
+		Text:         answer,
+		VideoTitle:   st.VideoTitle,
+		Correct:      p.Auto.Correct,
+		MatchedAlias: p.Auto.Matched,
+		Confidence:   p.Auto.Confidence,
+		Deadline:     deadline.UnixMilli(),
+		Timestamp:    time.Now().UnixMilli(),
+	}
+	m.mu.Unlock()
+
+	reqMsg, _ := json.Marshal(req)
+	m.SendToUser(roomID, host, websocket.TextMessage, reqMsg)
+	// 他の参加者には回答内容を伏せて判定中であることだけを知らせる
+	note, _ := json.Marshal(&model.ServerMessage{Type: "judging", User: user, Deadline: deadline.UnixMilli(), Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, note)
+
+	time.AfterFunc(time.Until(deadline), func() {
+		m.settleJudgement(roomID, st, p.ID, func(p *pendingJudgement) Verdict { return p.Auto })
+	})
+	return true
+}
+
+// JudgeAnswer accepts or rejects the answer waiting for the host's decision.
+// Only the host's connection can judge.
+func (m *RoomManager) JudgeAnswer(roomID string, conn *websocket.Conn, accept bool) error {
+	m.mu.RLock()
+	st := m.states[roomID]
+	if st == nil || st.Judgement == nil {
+		m.mu.RUnlock()
+		return ErrNoJudgement
+	}
+	if !st.isHost(conn) {
+		m.mu.RUnlock()
+		return ErrNotHost
+	}
+	id := st.Judgement.ID
+	m.mu.RUnlock()
+	return m.settleJudgement(roomID, st, id, func(*pendingJudgement) Verdict {
+		if accept {
+			return Verdict{Correct: true, Confidence: 1}
+		}
+		return Verdict{}
+	})
+}
+
+// settleJudgement applies the verdict to the pending answer with the given ID
+// in room state owner and proceeds as for an automatically judged answer. The
+// room is checked as well as the ID because IDs start over when a room is
+// opened again under the same name.
+func (m *RoomManager) settleJudgement(roomID string, owner *RoomState, id int, decide func(*pendingJudgement) Verdict) error {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st != owner || st.Judgement == nil || st.Judgement.ID != id {
+		m.mu.Unlock()
+		return ErrNoJudgement
+	}
+	p := st.Judgement
+	st.Judgement = nil
+	if st.Phase != PhasePlaying || st.Fastest != p.User {
+		m.mu.Unlock()
+		return ErrNoJudgement
+	}
+	verdict := decide(p)
+	next := st.applyVerdict(p.User, p.Answer, verdict)
+	m.mu.Unlock()
+
+	m.announceVerdict(roomID, p.User, verdict, next)
+	return nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import "testing"
+
+func TestHostJudging(t *testing.T) {
+	m, conns := testRoom(t, "r1", "alice", "bob", "carol")
+	st := m.states["r1"]
+	st.Settings.HostJudging = true
+
+	// ask starts a question, lets user buzz and sends answer to the host.
+	ask := func(user, answer string) {
+		t.Helper()
+		if _, err := m.NextVideo("r1"); err != nil {
+			t.Fatal(err)
+		}
+		m.StartQuestion("r1")
+		if first, _ := m.AddBuzz("r1", user); !first {
+			t.Fatalf("AddBuzz(%s) was not first", user)
+		}
+		if !m.requestJudgement("r1", user, answer) || st.Judgement == nil {
+			t.Fatalf("requestJudgement(%s) did not wait for the host", user)
+		}
+	}
+
+	ask("bob", st.VideoTitle)
+	if !m.requestJudgement("r1", "carol", "anything") || st.Judgement.User != "bob" {
+		t.Error("a second answer while judging should be ignored, not judged automatically")
+	}
+	for _, c := range conns[1:] {
+		if err := m.JudgeAnswer("r1", c, true); err != ErrNotHost {
+			t.Errorf("JudgeAnswer() by %s = %v, want ErrNotHost", st.Users[c], err)
+		}
+	}
+	if err := m.JudgeAnswer("r1", conns[0], false); err != nil {
+		t.Fatal(err)
+	}
+	q := st.Questions[len(st.Questions)-1]
+	if st.Scores["bob"] != 0 || st.Judgement != nil || st.Phase != PhaseReveal || len(q.Answers) != 1 || q.Answers[0].Correct {
+		t.Errorf("after rejecting: scores %v, phase %q, answers %+v", st.Scores, st.Phase, q.Answers)
+	}
+
+	ask("carol", "nonsense")
+	if err := m.JudgeAnswer("r1", conns[0], true); err != nil {
+		t.Fatal(err)
+	}
+	if st.Scores["carol"] != 1 || st.Phase != PhaseReveal {
+		t.Errorf("after accepting: scores %v, phase %q", st.Scores, st.Phase)
+	}
+	if err := m.JudgeAnswer("r1", conns[0], true); err != ErrNoJudgement {
+		t.Errorf("JudgeAnswer() with nothing pending = %v, want ErrNoJudgement", err)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// Strictness levels of the answer matcher.
+const (
+	StrictnessLenient = "lenient"
+	StrictnessNormal  = "normal"
+	StrictnessStrict  = "strict"
+)
+
+// ErrInvalidStrictness is returned for an unknown strictness level.
+var ErrInvalidStrictness = errors.New("invalid strictness")
+
+// matchLevel holds the thresholds of one strictness level.
+type matchLevel struct {
+	// maxErrorRate is the allowed edit distance relative to the answer length.
+	maxErrorRate float64
+	// minCoverage is the share of the whole title the answer has to cover, so
+	// that naming only the artist of an "artist / song" title is not enough.
+	minCoverage float64
+	// wordBoundary requires the answer to match whole words of the title.
+	wordBoundary bool
+}
+
+// matchLevels maps each strictness level to its thresholds.
+var matchLevels = map[string]matchLevel{
+	StrictnessLenient: {maxErrorRate: 0.3, minCoverage: 0.3, wordBoundary: false},
+	StrictnessNormal:  {maxErrorRate: 0.2, minCoverage: 0.7, wordBoundary: true},
+	StrictnessStrict:  {maxErrorRate: 0.1, minCoverage: 0.8, wordBoundary: true},
+}
+
+// ValidStrictness reports whether s names a strictness level. Empty means the default.
+func ValidStrictness(s string) bool {
+	if s == "" {
+		return true
+	}
+	_, ok := matchLevels[s]
+	return ok
+}
+
+// levelFor returns the thresholds of a strictness level, falling back to normal.
+func levelFor(strictness string) matchLevel {
+	if l, ok := matchLevels[strictness]; ok {
+		return l
+	}
+	return matchLevels[StrictnessNormal]
+}
+
+// segmentSeparators split a title into parts such as artist and song name.
+const segmentSeparators = "【】「」『』()（）[]［］<>〈〉《》/／|｜"
+
+// titleWords normalizes a title into space separated words. Brackets and
+// separators break words so that an answer never has to include them.
+func titleWords(title string) string {
+	title = strings.ReplaceAll(title, " - ", "/")
+	parts := strings.FieldsFunc(title, func(r rune) bool {
+		return strings.ContainsRune(segmentSeparators, r)
+	})
+	var segs []string
+	for _, p := range parts {
+		if p = normalizeAnswer(p); p != "" {
+			segs = append(segs, p)
+		}
+	}
+	return strings.Join(segs, " ")
+}
+
+// scoreAnswer rates how well answer matches target between 0 and 1. A score of
+// 0 means the answer is rejected at the given strictness. Spaces are ignored
+// when comparing, so only the position of word breaks matters.
+func scoreAnswer(target, answer, strictness string) float64 {
+	level := levelFor(strictness)
+	a := []rune(strings.ReplaceAll(normalizeAnswer(answer), " ", ""))
+	if len(a) == 0 {
+		return 0
+	}
+	allowed := int(level.maxErrorRate * float64(len(a)))
+	best := 0.0
+	words := titleWords(target)
+	joined := []rune(strings.ReplaceAll(words, " ", ""))
+	if len(joined) == 0 {
+		return 0
+	}
+	rate := func(dist, spanLen int) {
+		if dist > allowed {
+			return
+		}
+		coverage := float64(spanLen) / float64(len(joined))
+		if coverage > 1 {
+			coverage = 1
+		}
+		if coverage < level.minCoverage {
+			return
+		}
+		if score := (1 - float64(dist)/float64(len(a))) * coverage; score > best {
+			best = score
+		}
+	}
+	rate(bestWordSpan(words, a))
+	if !level.wordBoundary {
+		rate(substringDistance(joined, a), len(a))
+	}
+	return best
+}
+
+// bestWordSpan finds the run of whole words in seg closest to answer and
+// returns its edit distance and length in runes, ignoring spaces.
+func bestWordSpan(seg string, answer []rune) (int, int) {
+	words := strings.Fields(seg)
+	bestDist, bestLen := len(answer)+utf8.RuneCountInString(seg), 0
+	for i := range words {
+		for j := i + 1; j <= len(words); j++ {
+			span := []rune(strings.Join(words[i:j], ""))
+			d := levenshtein(span, answer)
+			if d < bestDist || (d == bestDist && len(span) > bestLen) {
+				bestDist, bestLen = d, len(span)
+			}
+			if len(span) > len(answer)*2 {
+				break
+			}
+		}
+	}
+	return bestDist, bestLen
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// substringDistance returns the smallest edit distance between answer and any
+// substring of text.
+func substringDistance(text, answer []rune) int {
+	// text 側の開始・終了位置を自由にした編集距離
+	prev := make([]int, len(text)+1)
+	cur := make([]int, len(text)+1)
+	for i := 1; i <= len(answer); i++ {
+		cur[0] = i
+		for j := 1; j <= len(text); j++ {
+			cost := 1
+			if answer[i-1] == text[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	best := prev[0]
+	for _, d := range prev {
+		if d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// min3 returns the smallest of three ints.
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
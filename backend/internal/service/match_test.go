@@ -0,0 +1,107 @@
+package service
+
+import "testing"
+
+func TestScoreAnswer(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		answer     string
+		strictness string
+		want       bool
+	}{
+		{"exact", "Pretender", "pretender", StrictnessNormal, true},
+		{"one typo", "Pretender", "pretendr", StrictnessNormal, true},
+		{"one typo strict", "Pretender", "pretendr", StrictnessStrict, false},
+		{"artist and song", "Official髭男dism / Pretender", "official髭男dism pretender", StrictnessStrict, true},
+		{"artist only", "Official髭男dism / Pretender", "official髭男dism", StrictnessNormal, false},
+		{"artist only strict", "Official髭男dism / Pretender", "official髭男dism", StrictnessStrict, false},
+		{"artist without separator", "YOASOBI アイドル", "yoasobi", StrictnessNormal, false},
+		{"single a", "Kaikai Kitan", "a", StrictnessNormal, false},
+		{"single a lenient", "Kaikai Kitan", "a", StrictnessLenient, false},
+		{"partial word", "Kaikai Kitan", "kita", StrictnessNormal, false},
+		{"too little of the title", "Kaikai Kitan", "kitan", StrictnessNormal, false},
+		{"empty", "Pretender", " ", StrictnessNormal, false},
+		{"spaces ignored", "yoru ni kakeru", "yorunikakeru", StrictnessNormal, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scoreAnswer(tt.target, tt.answer, tt.strictness) > 0
+			if got != tt.want {
+				t.Errorf("scoreAnswer(%q, %q, %q) accepted = %v, want %v", tt.target, tt.answer, tt.strictness, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestWordSpan(t *testing.T) {
+	tests := []struct {
+		seg      string
+		answer   string
+		wantDist int
+		wantLen  int
+	}{
+		{"yoru ni kakeru", "yorunikakeru", 0, 12},
+		{"yoru ni kakeru", "nikakeru", 0, 8},
+		{"yoru ni kakeru", "kakeru", 0, 6},
+		{"kaikai kitan", "kitam", 1, 5},
+	}
+	for _, tt := range tests {
+		dist, n := bestWordSpan(tt.seg, []rune(tt.answer))
+		if dist != tt.wantDist || n != tt.wantLen {
+			t.Errorf("bestWordSpan(%q, %q) = %d, %d, want %d, %d", tt.seg, tt.answer, dist, n, tt.wantDist, tt.wantLen)
+		}
+	}
+}
+
+func TestSubstringDistance(t *testing.T) {
+	tests := []struct {
+		text   string
+		answer string
+		want   int
+	}{
+		{"kaikaikitan", "kitan", 0},
+		{"kaikaikitan", "kitam", 1},
+		{"kaikaikitan", "a", 0},
+		{"pretender", "xyz", 3},
+	}
+	for _, tt := range tests {
+		if got := substringDistance([]rune(tt.text), []rune(tt.answer)); got != tt.want {
+			t.Errorf("substringDistance(%q, %q) = %d, want %d", tt.text, tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyJudgeReadings(t *testing.T) {
+	tests := []struct {
+		title  string
+		answer string
+		want   bool
+	}{
+		{"紅蓮華", "Gurenge", true},
+		{"紅蓮華", "ぐれんげ", true},
+		{"紅蓮華", "グレンゲ", true},
+		{"夜に駆ける", "yoru ni kakeru", true},
+		{"夜に駆ける", "よるにかける", true},
+		{"夜に駆ける", "asa ni kakeru", false},
+	}
+	judge := FuzzyJudge{Strictness: StrictnessNormal}
+	for _, tt := range tests {
+		accepted := append([]string{tt.title}, readingForms(tt.title)...)
+		if got := judge.Judge(accepted, tt.answer).Correct; got != tt.want {
+			t.Errorf("Judge(%q, %q) = %v, want %v", accepted, tt.answer, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyJudgeSongOfChannel(t *testing.T) {
+	// チャンネル名と同じアーティスト名は整形で外れるので曲名だけで正解になる
+	title := NewTitleCleaner().Clean("Official髭男dism - Pretender［Official Video］", "Official髭男dism", "")
+	judge := FuzzyJudge{Strictness: StrictnessNormal}
+	if !judge.Judge([]string{title}, "pretender").Correct {
+		t.Errorf("Judge(%q, %q) = incorrect, want correct", title, "pretender")
+	}
+	if judge.Judge([]string{title}, "official髭男dism").Correct {
+		t.Errorf("Judge(%q, %q) = correct, want incorrect", title, "official髭男dism")
+	}
+}
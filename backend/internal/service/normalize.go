@@ -0,0 +1,79 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// smallKana maps small hiragana to their full-size forms.
+var smallKana = map[rune]rune{
+	'ぁ': 'あ', 'ぃ': 'い', 'ぅ': 'う', 'ぇ': 'え', 'ぉ': 'お',
+	'っ': 'つ', 'ゃ': 'や', 'ゅ': 'ゆ', 'ょ': 'よ', 'ゎ': 'わ',
+	'ゕ': 'か', 'ゖ': 'け',
+}
+
+// kanaVowel returns the vowel a hiragana ends in, or 0 for other characters.
+func kanaVowel(r rune) byte {
+	roma := hiraganaRomaji[string(r)]
+	if roma == "" {
+		return 0
+	}
+	switch v := roma[len(roma)-1]; v {
+	case 'a', 'i', 'u', 'e', 'o':
+		return v
+	}
+	return 0
+}
+
+// longVowel reports whether the kana r only lengthens the vowel of prev, as in
+// とう, せい or かあ, the way ー does in katakana.
+func longVowel(prev, r rune) bool {
+	v := kanaVowel(prev)
+	switch r {
+	case 'あ':
+		return v == 'a'
+	case 'い':
+		return v == 'i' || v == 'e'
+	case 'う':
+		return v == 'u' || v == 'o'
+	case 'え':
+		return v == 'e'
+	case 'お':
+		return v == 'o'
+	}
+	return false
+}
+
+// normalizeAnswer brings a title or answer into a canonical form so that
+// equivalent inputs compare equal: NFKC folds half-width kana and full-width
+// romaji, katakana becomes hiragana, small kana become full size, long vowels
+// are shortened whether written with ー or with a kana (とうきょう and トーキョー
+// both become ときよ) and punctuation turns into word breaks. Words are joined by single spaces.
+func normalizeAnswer(s string) string {
+	s = strings.ToLower(norm.NFKC.String(s))
+	var b strings.Builder
+	var prev rune
+	for _, r := range s {
+		// カタカナはひらがなに寄せる（ヷ〜ヺは対応するひらがながないのでそのまま）
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+		}
+		// 小書きのかなは拗音などの一部なので長音とはみなさない
+		big, small := smallKana[r]
+		if small {
+			r = big
+		}
+		switch {
+		case r == 'ー', !small && longVowel(prev, r):
+			continue
+		case unicode.IsSpace(r), unicode.IsPunct(r), unicode.IsSymbol(r):
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,26 @@
+package service
+
+import "testing"
+
+func TestNormalizeAnswer(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"トーキョー", "ときよ"},
+		{"とうきょう", "ときよ"},
+		{"せんせい", "せんせ"},
+		{"おおきい", "おき"},
+		{"ｸﾞﾚﾝｹﾞ", "ぐれんげ"},
+		{"ｷｬﾝﾃﾞｨ", "きやんでい"},
+		{"ＹＯＡＳＯＢＩ", "yoasobi"},
+		{"Hello,  World!", "hello world"},
+		{"four", "four"},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeAnswer(tt.in); got != tt.want {
+			t.Errorf("normalizeAnswer(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
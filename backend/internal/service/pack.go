@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// ErrPackNotFound is returned when no question pack has the requested name.
+var ErrPackNotFound = errors.New("pack not found")
+
+// packExts lists the file extensions recognised as question packs, in lookup order.
+var packExts = []string{".json", ".yaml", ".yml"}
+
+// PackTrack is a single question of a question pack.
+type PackTrack struct {
+	VideoID string   `json:"videoId" yaml:"videoId"`
+	Title   string   `json:"title" yaml:"title"`
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases"`
+	Reading string   `json:"reading,omitempty" yaml:"reading"`
+	Start   int      `json:"start,omitempty" yaml:"start"`
+	Tags    []string `json:"tags,omitempty" yaml:"tags"`
+}
+
+// Pack is a curated list of questions stored as a JSON or YAML file.
+type Pack struct {
+	Name        string      `json:"name" yaml:"-"`
+	Description string      `json:"description,omitempty" yaml:"description"`
+	Tracks      []PackTrack `json:"tracks" yaml:"tracks"`
+}
+
+// PackSummary is the short form of a pack used in listings.
+type PackSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tracks      int      `json:"tracks"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// PackStore loads question packs from the pack directory. Files are read on
+// every lookup so packs can be edited without restarting the server.
+type PackStore struct{}
+
+// NewPackStore creates a PackStore.
+func NewPackStore() *PackStore {
+	return &PackStore{}
+}
+
+// validPackName reports whether name is safe to use as a file name.
+func validPackName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`) && !strings.HasPrefix(name, ".")
+}
+
+// readPack parses a pack file. The pack is named after the file.
+func readPack(path string) (*Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Pack
+	ext := filepath.Ext(path)
+	if ext == ".json" {
+		err = json.Unmarshal(data, &p)
+	} else {
+		err = yaml.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	p.Name = strings.TrimSuffix(filepath.Base(path), ext)
+	return &p, nil
+}
+
+// Get loads the pack stored under name.
+func (s *PackStore) Get(name string) (*Pack, error) {
+	if !validPackName(name) {
+		return nil, ErrPackNotFound
+	}
+	for _, ext := range packExts {
+		p, err := readPack(filepath.Join(config.PackDir, name+ext))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return p, err
+	}
+	return nil, ErrPackNotFound
+}
+
+// List returns summaries of all packs in the pack directory, sorted by name.
+func (s *PackStore) List() ([]PackSummary, error) {
+	entries, err := os.ReadDir(config.PackDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []PackSummary{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := make([]PackSummary, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !isPackFile(e.Name()) {
+			continue
+		}
+		p, err := readPack(filepath.Join(config.PackDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		list = append(list, p.Summary())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list, nil
+}
+
+// isPackFile reports whether the file name has a pack extension.
+func isPackFile(name string) bool {
+	ext := filepath.Ext(name)
+	for _, e := range packExts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Videos returns the pack's tracks as a video pool.
+func (p *Pack) Videos() []VideoItem {
+	list := make([]VideoItem, 0, len(p.Tracks))
+	for _, t := range p.Tracks {
+		if t.VideoID == "" || t.Title == "" {
+			continue
+		}
+		list = append(list, VideoItem{
+			ID:      t.VideoID,
+			Title:   t.Title,
+			Start:   t.Start,
+			Reading: t.Reading,
+			Aliases: t.Aliases,
+			Tags:    t.Tags,
+			Pack:    p.Name,
+		})
+	}
+	return list
+}
+
+// Summary returns the short form of the pack.
+func (p *Pack) Summary() PackSummary {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, t := range p.Tracks {
+		for _, tag := range t.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return PackSummary{Name: p.Name, Description: p.Description, Tracks: len(p.Tracks), Tags: tags}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// ErrQuotaExhausted is returned instead of calling the YouTube API once the
+// daily quota budget has been used up.
+var ErrQuotaExhausted = errors.New("youtube api quota budget exhausted")
+
+// quotaCosts is the number of quota units each API call type consumes.
+var quotaCosts = map[string]int{
+	"playlistItems": 1,
+	"videos":        1,
+	"search":        100,
+}
+
+// quotaLocation is the time zone in which the YouTube quota resets at midnight.
+var quotaLocation = func() *time.Location {
+	if loc, err := time.LoadLocation("America/Los_Angeles"); err == nil {
+		return loc
+	}
+	return time.FixedZone("PST", -8*60*60)
+}()
+
+// QuotaUsage reports the quota spent today.
+type QuotaUsage struct {
+	Date      string         `json:"date"`
+	Used      int            `json:"used"`
+	Budget    int            `json:"budget"`
+	Remaining int            `json:"remaining"`
+	Calls     map[string]int `json:"calls"`
+	Units     map[string]int `json:"units"`
+	Warning   bool           `json:"warning"`
+	Exhausted bool           `json:"exhausted"`
+}
+
+// QuotaTracker counts the YouTube API quota units spent per day and call type
+// and refuses calls that would exceed config.YouTubeQuotaBudget. Usage is
+// saved in the data directory so restarts do not reset the count.
+type QuotaTracker struct {
+	mu     sync.Mutex
+	once   sync.Once
+	path   string
+	usage  QuotaUsage
+	warned bool
+}
+
+// NewQuotaTracker creates a QuotaTracker. Saved usage is loaded on first use.
+func NewQuotaTracker() *QuotaTracker {
+	return &QuotaTracker{}
+}
+
+// load reads the saved usage once.
+func (q *QuotaTracker) load() {
+	q.once.Do(func() {
+		q.path = filepath.Join(config.DataDir, "quota.json")
+		if err := readJSONFile(q.path, &q.usage); err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("load quota usage: %v", err)
+			}
+			// 途中まで読めた内容は使わず rollover で数え直す
+			q.usage = QuotaUsage{}
+			return
+		}
+		q.warned = q.usage.Warning
+	})
+}
+
+// save writes the usage to disk. The caller must hold q.mu.
+func (q *QuotaTracker) save() error {
+	return writeJSONFile(q.path, q.usage)
+}
+
+// rollover starts a new count when the quota day has changed. The caller must hold q.mu.
+func (q *QuotaTracker) rollover() {
+	today := time.Now().In(quotaLocation).Format("2006-01-02")
+	if q.usage.Date == today {
+		return
+	}
+	q.usage = QuotaUsage{Date: today, Calls: make(map[string]int), Units: make(map[string]int)}
+	q.warned = false
+}
+
+// refresh fills in the budget dependent fields. The caller must hold q.mu.
+func (q *QuotaTracker) refresh() {
+	q.usage.Budget = config.YouTubeQuotaBudget
+	q.usage.Remaining = q.usage.Budget - q.usage.Used
+	if q.usage.Remaining < 0 {
+		q.usage.Remaining = 0
+	}
+	q.usage.Warning = q.usage.Used*100 >= q.usage.Budget*config.YouTubeQuotaWarn
+	q.usage.Exhausted = q.usage.Remaining == 0
+}
+
+// Charge records a call of the given type, such as "videos". It returns
+// ErrQuotaExhausted without recording anything when the call would exceed the
+// budget.
+func (q *QuotaTracker) Charge(call string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.load()
+	q.rollover()
+	cost, ok := quotaCosts[call]
+	if !ok {
+		cost = 1
+	}
+	if q.usage.Used+cost > config.YouTubeQuotaBudget {
+		return ErrQuotaExhausted
+	}
+	q.usage.Used += cost
+	q.usage.Calls[call]++
+	q.usage.Units[call] += cost
+	q.refresh()
+	if q.usage.Warning && !q.warned {
+		q.warned = true
+		log.Printf("youtube api quota: %d of %d units used today", q.usage.Used, q.usage.Budget)
+	}
+	if err := q.save(); err != nil {
+		log.Printf("save quota usage: %v", err)
+	}
+	return nil
+}
+
+// Exhausted reports whether no further API calls can be made today.
+func (q *QuotaTracker) Exhausted() bool {
+	return q.Usage().Exhausted
+}
+
+// Usage returns the quota spent today.
+func (q *QuotaTracker) Usage() QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.load()
+	q.rollover()
+	q.refresh()
+	u := q.usage
+	u.Calls = make(map[string]int, len(q.usage.Calls))
+	for k, v := range q.usage.Calls {
+		u.Calls[k] = v
+	}
+	u.Units = make(map[string]int, len(q.usage.Units))
+	for k, v := range q.usage.Units {
+		u.Units[k] = v
+	}
+	return u
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"intro-quiz/backend/internal/config"
+)
+
+func TestQuotaTrackerCharge(t *testing.T) {
+	useDataDir(t)
+	old := config.YouTubeQuotaBudget
+	config.YouTubeQuotaBudget = 102
+	t.Cleanup(func() { config.YouTubeQuotaBudget = old })
+
+	q := NewQuotaTracker()
+	for _, call := range []string{"videos", "search"} {
+		if err := q.Charge(call); err != nil {
+			t.Fatalf("Charge(%q) = %v", call, err)
+		}
+	}
+	if err := q.Charge("search"); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("Charge over budget = %v, want ErrQuotaExhausted", err)
+	}
+
+	u := NewQuotaTracker().Usage()
+	if u.Used != 101 || u.Remaining != 1 || u.Calls["search"] != 1 || u.Units["search"] != 100 {
+		t.Errorf("Usage() after reload = %+v", u)
+	}
+	if u.Exhausted {
+		t.Error("Usage().Exhausted = true with units left")
+	}
+}
+
+func TestQuotaTrackerKeepsCorruptFile(t *testing.T) {
+	dir := useDataDir(t)
+	path := filepath.Join(dir, "quota.json")
+	broken := []byte(`{"date":"2026-10-19","used":`)
+	if err := os.WriteFile(path, broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewQuotaTracker()
+	if err := q.Charge("videos"); err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Usage().Used; got != 1 {
+		t.Errorf("Usage().Used = %d, want 1", got)
+	}
+	if got, err := os.ReadFile(path + ".corrupt"); err != nil || string(got) != string(broken) {
+		t.Errorf("corrupt file = %q, %v; want %q", got, err, broken)
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+const (
+	// initialRating is assigned to players without any finished games.
+	initialRating = 1500.0
+	// ratingK is the maximum rating change against a single opponent.
+	ratingK = 32.0
+)
+
+// PlayerRating is the persisted rating of a single player.
+type PlayerRating struct {
+	User      string    `json:"user"`
+	Rating    float64   `json:"rating"`
+	Games     int       `json:"games"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RatingService keeps Elo-style ratings of players across games.
+type RatingService struct {
+	mu      sync.Mutex
+	once    sync.Once
+	path    string
+	ratings map[string]*PlayerRating
+}
+
+// NewRatingService creates a RatingService. Ratings are loaded lazily from the
+// configured data directory on first use.
+func NewRatingService() *RatingService {
+	return &RatingService{ratings: make(map[string]*PlayerRating)}
+}
+
+// load reads the ratings file once.
+func (s *RatingService) load() {
+	s.once.Do(func() {
+		s.path = filepath.Join(config.DataDir, "ratings.json")
+		var list []*PlayerRating
+		if err := readJSONFile(s.path, &list); err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("load ratings: %v", err)
+			}
+			return
+		}
+		for _, r := range list {
+			s.ratings[r.User] = r
+		}
+	})
+}
+
+// save writes all ratings to disk. The caller must hold s.mu.
+func (s *RatingService) save() error {
+	return writeJSONFile(s.path, s.sortedLocked())
+}
+
+// sortedLocked returns copies of all ratings ordered from strongest to weakest.
+func (s *RatingService) sortedLocked() []PlayerRating {
+	list := make([]PlayerRating, 0, len(s.ratings))
+	for _, r := range s.ratings {
+		list = append(list, *r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Rating != list[j].Rating {
+			return list[i].Rating > list[j].Rating
+		}
+		return list[i].User < list[j].User
+	})
+	return list
+}
+
+// rating returns the current rating of a user. The caller must hold s.mu.
+func (s *RatingService) rating(user string) float64 {
+	if r, ok := s.ratings[user]; ok {
+		return r.Rating
+	}
+	return initialRating
+}
+
+// Get returns the rating of a single player and whether it has been recorded.
+func (s *RatingService) Get(user string) (PlayerRating, bool) {
+	s.load()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.ratings[user]
+	if !ok {
+		return PlayerRating{User: user, Rating: initialRating}, false
+	}
+	return *r, true
+}
+
+// List returns all recorded ratings from strongest to weakest.
+func (s *RatingService) List() []PlayerRating {
+	s.load()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedLocked()
+}
+
+// Lookup returns the rounded ratings of the given users.
+func (s *RatingService) Lookup(users []string) map[string]int {
+	s.load()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[string]int, len(users))
+	for _, u := range users {
+		res[u] = int(math.Round(s.rating(u)))
+	}
+	return res
+}
+
+// Update applies the final standings of a game and returns the new rounded
+// ratings. Every pair of players is scored as a win, loss or draw by rank and
+// the rating change is scaled by the number of opponents.
+func (s *RatingService) Update(standings []model.Standing) map[string]int {
+	s.load()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[string]int, len(standings))
+	if len(standings) < 2 {
+		for _, st := range standings {
+			res[st.User] = int(math.Round(s.rating(st.User)))
+		}
+		return res
+	}
+	k := ratingK / float64(len(standings)-1)
+	deltas := make([]float64, len(standings))
+	for i, a := range standings {
+		ra := s.rating(a.User)
+		for j, b := range standings {
+			if i == j {
+				continue
+			}
+			rb := s.rating(b.User)
+			expected := 1 / (1 + math.Pow(10, (rb-ra)/400))
+			actual := 0.5
+			if a.Rank < b.Rank {
+				actual = 1
+			} else if a.Rank > b.Rank {
+				actual = 0
+			}
+			deltas[i] += k * (actual - expected)
+		}
+	}
+	now := time.Now()
+	for i, st := range standings {
+		r, ok := s.ratings[st.User]
+		if !ok {
+			r = &PlayerRating{User: st.User, Rating: initialRating}
+			s.ratings[st.User] = r
+		}
+		r.Rating += deltas[i]
+		r.Games++
+		r.UpdatedAt = now
+		res[st.User] = int(math.Round(r.Rating))
+	}
+	if err := s.save(); err != nil {
+		log.Printf("save ratings: %v", err)
+	}
+	return res
+}
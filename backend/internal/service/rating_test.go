@@ -0,0 +1,65 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// useDataDir points config.DataDir at a fresh temporary directory for the test.
+func useDataDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	old := config.DataDir
+	config.DataDir = dir
+	t.Cleanup(func() { config.DataDir = old })
+	return dir
+}
+
+func TestRatingServicePersists(t *testing.T) {
+	useDataDir(t)
+	s := NewRatingService()
+	s.Update([]model.Standing{
+		{User: "alice", Score: 3, Rank: 1},
+		{User: "bob", Score: 1, Rank: 2},
+	})
+
+	reloaded := NewRatingService()
+	alice, ok := reloaded.Get("alice")
+	if !ok || alice.Games != 1 || alice.Rating <= initialRating {
+		t.Fatalf("Get(alice) = %+v, %v; want one game above %v", alice, ok, initialRating)
+	}
+	bob, ok := reloaded.Get("bob")
+	if !ok || bob.Rating >= initialRating {
+		t.Fatalf("Get(bob) = %+v, %v; want below %v", bob, ok, initialRating)
+	}
+}
+
+func TestRatingServiceKeepsCorruptFile(t *testing.T) {
+	dir := useDataDir(t)
+	path := filepath.Join(dir, "ratings.json")
+	broken := []byte(`[{"user":"alice","rating":`)
+	if err := os.WriteFile(path, broken, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRatingService()
+	s.Update([]model.Standing{
+		{User: "carol", Score: 2, Rank: 1},
+		{User: "dave", Score: 0, Rank: 2},
+	})
+
+	got, err := os.ReadFile(path + ".corrupt")
+	if err != nil {
+		t.Fatalf("corrupt file not kept: %v", err)
+	}
+	if string(got) != string(broken) {
+		t.Errorf("corrupt file = %q, want %q", got, broken)
+	}
+	if _, ok := NewRatingService().Get("carol"); !ok {
+		t.Error("ratings saved after the corrupt file was moved aside are missing")
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// maxReactionsPerInterval limits how many reactions one connection can add to a single broadcast.
+const maxReactionsPerInterval = 3
+
+// reactionWhitelist holds the emoji players and spectators may send.
+var reactionWhitelist = map[string]bool{
+	"👏":  true,
+	"😂":  true,
+	"😮":  true,
+	"🔥":  true,
+	"🎉":  true,
+	"😭":  true,
+	"🤔":  true,
+	"❤️": true,
+}
+
+// Reasons for dropping a reaction.
+var (
+	ErrReactionUnknown   = errors.New("reaction not allowed")
+	ErrReactionThrottled = errors.New("too many reactions")
+)
+
+// AddReaction counts a reaction towards the room's next "reactions" broadcast.
+// Reactions are aggregated and flushed at most once per configured interval.
+// The cap is counted per connection so that players and spectators alike
+// cannot get around it by sending another name.
+func (m *RoomManager) AddReaction(roomID string, conn *websocket.Conn, emoji string) error {
+	if !reactionWhitelist[emoji] {
+		return ErrReactionUnknown
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.states[roomID]
+	if st == nil {
+		return ErrReactionUnknown
+	}
+	if st.ReactionSenders[conn] >= maxReactionsPerInterval {
+		return ErrReactionThrottled
+	}
+	st.ReactionSenders[conn]++
+	st.Reactions[emoji]++
+	if !st.ReactionPending {
+		st.ReactionPending = true
+		time.AfterFunc(time.Duration(config.ReactionInterval)*time.Millisecond, func() {
+			m.flushReactions(roomID, st)
+		})
+	}
+	return nil
+}
+
+// flushReactions broadcasts the aggregated reactions of room state owner and
+// resets the counters.
+func (m *RoomManager) flushReactions(roomID string, owner *RoomState) {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st != owner {
+		m.mu.Unlock()
+		return
+	}
+	counts := st.Reactions
+	st.Reactions = make(map[string]int)
+	st.ReactionSenders = make(map[*websocket.Conn]int)
+	st.ReactionPending = false
+	m.mu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+	msg, _ := json.Marshal(&model.ServerMessage{Type: "reactions", Reactions: counts, Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, msg)
+}
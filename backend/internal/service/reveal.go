@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/model"
+)
+
+// Outcomes of a question shown in the reveal.
+const (
+	OutcomeCorrect   = "correct"
+	OutcomeIncorrect = "incorrect"
+	OutcomeTimeout   = "timeout"
+	OutcomeSkip      = "skip"
+)
+
+// pointsPerCorrect is the score awarded for a correct answer.
+const pointsPerCorrect = 1
+
+// ErrNotHost is returned when a host-only command is sent by another user.
+var ErrNotHost = errors.New("only the host can do this")
+
+// ErrCannotStart is returned when a question is started while one is already
+// running or after the game is over.
+var ErrCannotStart = errors.New("a question can only be started from the lobby or the ready check")
+
+// Reveal ends the current question and returns the "reveal" message describing
+// it. It reports false when no question is waiting to be revealed.
+func (m *RoomManager) Reveal(roomID, outcome string) (*model.ServerMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.states[roomID]
+	if st == nil || st.Phase != PhasePlaying {
+		return nil, false
+	}
+	if st.TimeoutCancel != nil {
+		close(st.TimeoutCancel)
+		st.TimeoutCancel = nil
+	}
+	st.setPhase(PhaseReveal)
+	st.Active = false
+	st.Fastest = ""
+	st.BuzzOrder = nil
+	st.Judgement = nil
+	msg := &model.ServerMessage{
+		Type:       "reveal",
+		Phase:      PhaseReveal,
+		Outcome:    outcome,
+		VideoID:    st.VideoID,
+		VideoTitle: st.VideoTitle,
+		Channel:    st.VideoChannel,
+		Scores:     copyScores(st.Scores),
+		Timestamp:  time.Now().UnixMilli(),
+	}
+	if q := st.currentQuestion(); q != nil {
+		q.Outcome = outcome
+		msg.User = q.AnsweredBy
+		msg.ReactionMs = q.ReactionMs
+		msg.Points = q.Points
+	}
+	return msg, true
+}
+
+// SkipQuestion checks that conn may skip the current question. Only the host
+// can skip; the caller then reveals the question with OutcomeSkip.
+func (m *RoomManager) SkipQuestion(roomID string, conn *websocket.Conn) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil || st.Phase != PhasePlaying {
+		return errors.New("no question to skip")
+	}
+	if !st.isHost(conn) {
+		return ErrNotHost
+	}
+	return nil
+}
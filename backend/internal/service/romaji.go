@@ -0,0 +1,199 @@
+package service
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:embed readings.tsv
+var readingsTSV string
+
+// readings maps kanji titles and names to their hiragana reading.
+var readings = parseReadings(readingsTSV)
+
+// readingKeys lists the multi-character reading entries, longest first, so
+// they can be replaced inside longer titles.
+var readingKeys = sortedReadingKeys(readings)
+
+// parseReadings reads "surface<TAB>reading" lines, skipping comments.
+func parseReadings(tsv string) map[string]string {
+	m := make(map[string]string)
+	for _, line := range strings.Split(tsv, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "\t"); ok {
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return m
+}
+
+// sortedReadingKeys returns the keys with two or more runes, longest first.
+func sortedReadingKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		if utf8.RuneCountInString(k) > 1 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		li, lj := utf8.RuneCountInString(keys[i]), utf8.RuneCountInString(keys[j])
+		if li != lj {
+			return li > lj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// kanaReading returns text with known kanji words replaced by their reading and
+// katakana folded to hiragana. Single-kanji entries only apply to whole titles.
+func kanaReading(text string) string {
+	s := strings.TrimSpace(norm.NFKC.String(text))
+	if r, ok := readings[s]; ok {
+		s = r
+	} else {
+		for _, k := range readingKeys {
+			s = strings.ReplaceAll(s, k, readings[k])
+		}
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// hiraganaRomaji is the Hepburn spelling of each hiragana.
+var hiraganaRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ゔ": "vu",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しぇ": "she", "しょ": "sho",
+	"ちゃ": "cha", "ちゅ": "chu", "ちぇ": "che", "ちょ": "cho",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じぇ": "je", "じょ": "jo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"てぃ": "ti", "でぃ": "di", "とぅ": "tu", "どぅ": "du",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo",
+	"ゔぁ": "va", "ゔぃ": "vi", "ゔぇ": "ve", "ゔぉ": "vo",
+}
+
+// toRomaji transliterates the hiragana in s to Hepburn romaji. Other
+// characters, including any kanji left without a reading, are kept.
+func toRomaji(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	double := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == 'っ' {
+			double = true
+			continue
+		}
+		if r == 'ー' {
+			continue
+		}
+		roma, ok := "", false
+		if i+1 < len(runes) {
+			roma, ok = hiraganaRomaji[string(runes[i:i+2])]
+			if ok {
+				i++
+			}
+		}
+		if !ok {
+			roma, ok = hiraganaRomaji[string(r)]
+		}
+		if !ok {
+			double = false
+			b.WriteRune(r)
+			continue
+		}
+		if double {
+			// 促音は次の子音を重ねる（ch は tch）
+			if strings.HasPrefix(roma, "ch") {
+				b.WriteByte('t')
+			} else if roma[0] != 'a' && roma[0] != 'i' && roma[0] != 'u' && roma[0] != 'e' && roma[0] != 'o' && roma[0] != 'n' {
+				b.WriteByte(roma[0])
+			}
+			double = false
+		}
+		b.WriteString(roma)
+	}
+	return b.String()
+}
+
+// romajiShort shortens the long vowels of Hepburn romaji, as in tokyo for toukyou.
+var romajiShort = strings.NewReplacer("ou", "o", "oo", "o", "uu", "u")
+
+// romajiFold maps Hepburn spellings to their Kunrei forms and shortens long
+// vowels, as in syozyo for shoujo.
+var romajiFold = strings.NewReplacer(
+	"shi", "si", "sh", "sy", "chi", "ti", "ch", "ty", "tsu", "tu",
+	"fu", "hu", "ji", "zi", "j", "zy", "wo", "o", "ou", "o", "oo", "o", "uu", "u",
+)
+
+// hasKana reports whether s contains hiragana or katakana.
+func hasKana(s string) bool {
+	for _, r := range s {
+		if (r >= 'ぁ' && r <= 'ゖ') || (r >= 'ァ' && r <= 'ヺ') {
+			return true
+		}
+	}
+	return false
+}
+
+// readingForms returns the kana reading and romaji spellings of text when they
+// differ from it, so that answers typed in either form are accepted. The romaji
+// is given in Hepburn, with long vowels shortened and in Kunrei spelling; other
+// Latin text is never respelled, so English titles only match as written.
+func readingForms(text string) []string {
+	kana := kanaReading(text)
+	if !hasKana(kana) {
+		return nil
+	}
+	var forms []string
+	if kana != text {
+		forms = append(forms, kana)
+	}
+	roma := strings.ToLower(toRomaji(kana))
+	forms = append(forms, roma)
+	short := romajiShort.Replace(roma)
+	if short != roma {
+		forms = append(forms, short)
+	}
+	if kunrei := romajiFold.Replace(roma); kunrei != short {
+		forms = append(forms, kunrei)
+	}
+	return forms
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToRomaji(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"ぐれんげ", "gurenge"},
+		{"よるにかける", "yorunikakeru"},
+		{"しょうじょ", "shoujo"},
+		{"きっぷ", "kippu"},
+		{"まっちゃ", "matcha"},
+		{"らーめん", "ramen"},
+		{"YOASOBI あいどる", "YOASOBI aidoru"},
+	}
+	for _, tt := range tests {
+		if got := toRomaji(tt.in); got != tt.want {
+			t.Errorf("toRomaji(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadingForms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"紅蓮華", []string{"ぐれんげ", "gurenge"}},
+		{"しょうじょ", []string{"shoujo", "shojo", "syozyo"}},
+		{"とうきょう", []string{"toukyou", "tokyo"}},
+		{"Four Seasons", nil},
+	}
+	for _, tt := range tests {
+		if got := readingForms(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("readingForms(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
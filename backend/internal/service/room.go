@@ -1,11 +1,12 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"math/rand"
-	"os"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
@@ -15,24 +16,84 @@ import (
 	"intro-quiz/backend/internal/model"
 )
 
-// RoomManager manages WebSocket connections grouped by room ID.
+// Room phases.
+const (
+	PhaseLobby        = "lobby"
+	PhasePlaying      = "playing"
+	PhaseReveal       = "reveal"
+	PhaseIntermission = "intermission"
+	PhaseWaiting      = "waiting"
+	PhaseFinished     = "finished"
+)
+
+// RoomState holds the quiz state of a single room.
 type RoomState struct {
 	Fastest         string
 	Active          bool
 	Ready           map[string]bool
 	Users           map[*websocket.Conn]string
+	Host            string
+	Settings        model.RoomSettings
+	Phase           string
+	PhaseDeadline   time.Time
+	PhaseGen        int
+	Round           int
+	Scores          map[string]int
+	Tiebreak        *Tiebreak
 	BuzzOrder       []string
+	VideoID         string
 	VideoTitle      string
+	VideoChannel    string
+	VideoStart      int
+	VideoAnswers    []string
 	PlaylistID      string
+	DailyDate       string
 	RemainingVideos []VideoItem
+	PlayedVideos    []string
+	Questions       []QuestionRecord
+	StartedAt       time.Time
+	QuestionStart   time.Time
+	BuzzTimes       map[string]time.Time
+	ChatLog         map[string][]time.Time
+	Reactions       map[string]int
+	ReactionSenders map[*websocket.Conn]int
+	ReactionPending bool
 	TimeoutCancel   chan struct{}
+	GameOver        bool
+	Judgement       *pendingJudgement
+	JudgementGen    int
+	Rejections      map[string]rejectedAnswer
+	Appeal          *appeal
+	AppealGen       int
+}
+
+// newRoomState creates an empty RoomState in the lobby phase.
+func newRoomState() *RoomState {
+	return &RoomState{
+		Ready:           make(map[string]bool),
+		Users:           make(map[*websocket.Conn]string),
+		Scores:          make(map[string]int),
+		ChatLog:         make(map[string][]time.Time),
+		Reactions:       make(map[string]int),
+		ReactionSenders: make(map[*websocket.Conn]int),
+		Settings:        model.RoomSettings{AutoAdvance: config.AutoAdvance, Strictness: config.AnswerStrictness, Judge: config.AnswerJudge},
+		Phase:           PhaseLobby,
+	}
 }
 
 // RoomManager manages WebSocket connections grouped by room ID and quiz state.
 type RoomManager struct {
-	rooms  map[string]map[*websocket.Conn]bool
-	states map[string]*RoomState
-	mu     sync.RWMutex
+	rooms      map[string]map[*websocket.Conn]*sync.Mutex
+	states     map[string]*RoomState
+	ratings    *RatingService
+	history    *HistoryStore
+	daily      *DailyService
+	challenges *ChallengeStore
+	packs      *PackStore
+	source     *VideoSource
+	youtube    *YouTubeClient
+	aliases    *AliasStore
+	mu         sync.RWMutex
 }
 
 // ResetReady sets all ready states to false and returns the updated states.
@@ -49,6 +110,30 @@ func (m *RoomManager) ResetReady(roomID string) map[string]bool {
 	return copyReady(st.Ready)
 }
 
+// currentQuestion returns the record of the question being played, if any.
+func (st *RoomState) currentQuestion() *QuestionRecord {
+	if len(st.Questions) == 0 {
+		return nil
+	}
+	return &st.Questions[len(st.Questions)-1]
+}
+
+// isHost reports whether conn has joined as the host of the room. The caller
+// must hold the lock.
+func (st *RoomState) isHost(conn *websocket.Conn) bool {
+	name, ok := st.Users[conn]
+	return ok && name == st.Host
+}
+
+// copyScores returns a copy of the score map.
+func copyScores(src map[string]int) map[string]int {
+	dst := make(map[string]int)
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
 // copyReady returns a copy of ready state map.
 func copyReady(src map[string]bool) map[string]bool {
 	dst := make(map[string]bool)
@@ -60,22 +145,70 @@ func copyReady(src map[string]bool) map[string]bool {
 
 // NewRoomManager creates a new RoomManager.
 func NewRoomManager() *RoomManager {
+	packs := NewPackStore()
+	youtube := NewYouTubeClient(nil, "", "")
+	youtube.Cache = NewYouTubeCache()
+	youtube.Quota = NewQuotaTracker()
+	source := NewVideoSource(packs, NewTitleCleaner(), youtube)
 	return &RoomManager{
-		rooms:  make(map[string]map[*websocket.Conn]bool),
-		states: make(map[string]*RoomState),
+		rooms:      make(map[string]map[*websocket.Conn]*sync.Mutex),
+		states:     make(map[string]*RoomState),
+		ratings:    NewRatingService(),
+		history:    NewHistoryStore(),
+		daily:      NewDailyService(source),
+		challenges: NewChallengeStore(),
+		packs:      packs,
+		source:     source,
+		youtube:    youtube,
+		aliases:    NewAliasStore(),
 	}
 }
 
+// YouTube returns the YouTube API client shared by all rooms.
+func (m *RoomManager) YouTube() *YouTubeClient {
+	return m.youtube
+}
+
+// Aliases returns the store of accepted answer aliases.
+func (m *RoomManager) Aliases() *AliasStore {
+	return m.aliases
+}
+
+// Packs returns the question pack store.
+func (m *RoomManager) Packs() *PackStore {
+	return m.packs
+}
+
+// Challenges returns the store of recorded solo runs.
+func (m *RoomManager) Challenges() *ChallengeStore {
+	return m.challenges
+}
+
+// Daily returns the daily challenge service shared by all rooms.
+func (m *RoomManager) Daily() *DailyService {
+	return m.daily
+}
+
+// History returns the match history store shared by all rooms.
+func (m *RoomManager) History() *HistoryStore {
+	return m.history
+}
+
+// Ratings returns the rating service shared by all rooms.
+func (m *RoomManager) Ratings() *RatingService {
+	return m.ratings
+}
+
 // Join adds a connection to a room.
 func (m *RoomManager) Join(roomID string, conn *websocket.Conn) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if _, ok := m.rooms[roomID]; !ok {
-		m.rooms[roomID] = make(map[*websocket.Conn]bool)
+		m.rooms[roomID] = make(map[*websocket.Conn]*sync.Mutex)
 	}
-	m.rooms[roomID][conn] = true
+	m.rooms[roomID][conn] = &sync.Mutex{}
 	if _, ok := m.states[roomID]; !ok {
-		m.states[roomID] = &RoomState{Ready: make(map[string]bool), Users: make(map[*websocket.Conn]string)}
+		m.states[roomID] = newRoomState()
 	}
 }
 
@@ -85,13 +218,19 @@ func (m *RoomManager) RegisterUser(roomID string, conn *websocket.Conn, name str
 	defer m.mu.Unlock()
 	st := m.states[roomID]
 	if st == nil {
-		st = &RoomState{Ready: make(map[string]bool), Users: make(map[*websocket.Conn]string)}
+		st = newRoomState()
 		m.states[roomID] = st
 	}
 	st.Users[conn] = name
 	if _, ok := st.Ready[name]; !ok {
 		st.Ready[name] = false
 	}
+	if _, ok := st.Scores[name]; !ok {
+		st.Scores[name] = 0
+	}
+	if st.Host == "" {
+		st.Host = name
+	}
 	return copyReady(st.Ready)
 }
 
@@ -104,6 +243,10 @@ func (m *RoomManager) SetReady(roomID, name string) (bool, map[string]bool) {
 		return false, nil
 	}
 	st.Ready[name] = true
+	if st.Phase != PhaseLobby && st.Phase != PhaseWaiting {
+		// 正解発表中やインターミッション中、ゲーム終了後は次の問題を始めない
+		return false, copyReady(st.Ready)
+	}
 	all := true
 	for _, v := range st.Ready {
 		if !v {
@@ -114,36 +257,127 @@ func (m *RoomManager) SetReady(roomID, name string) (bool, map[string]bool) {
 	return all, copyReady(st.Ready)
 }
 
-// Leave removes a connection from a room.
+// Leave removes a connection from a room. When the leaving player holds the
+// right to answer, it passes to the next player in the buzz order or, when
+// nobody is left to answer, the question ends.
 func (m *RoomManager) Leave(roomID string, conn *websocket.Conn) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	next, handoff := "", false
+	var order []string
 	if clients, ok := m.rooms[roomID]; ok {
 		delete(clients, conn)
 		if st, ok := m.states[roomID]; ok {
 			if name, exists := st.Users[conn]; exists {
 				delete(st.Users, conn)
 				delete(st.Ready, name)
+				if st.Host == name {
+					// 残っているユーザーにホストを引き継ぐ
+					st.Host = ""
+					for _, u := range st.Users {
+						st.Host = u
+						break
+					}
+				}
+				next, handoff = st.dropBuzz(name)
+				order = append([]string(nil), st.BuzzOrder...)
 			}
 		}
 		if len(clients) == 0 {
 			delete(m.rooms, roomID)
 			delete(m.states, roomID)
+			handoff = false
 		}
 	}
+	m.mu.Unlock()
+
+	if !handoff {
+		return
+	}
+	if next == "" {
+		m.advance(roomID, OutcomeIncorrect)
+		return
+	}
+	orderMsg, _ := json.Marshal(&model.ServerMessage{Type: "buzz_order", BuzzOrder: order, Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, orderMsg)
+	nextMsg, _ := json.Marshal(&model.ServerMessage{Type: "buzz_result", User: next, Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, nextMsg)
+}
+
+// dropBuzz removes user from the buzz order of the running question. It
+// reports true when user held the right to answer, together with the player it
+// passes to, or "" when nobody is left. The caller must hold the lock.
+func (st *RoomState) dropBuzz(user string) (string, bool) {
+	if st.Phase != PhasePlaying {
+		return "", false
+	}
+	for i, u := range st.BuzzOrder {
+		if u == user {
+			st.BuzzOrder = append(st.BuzzOrder[:i], st.BuzzOrder[i+1:]...)
+			break
+		}
+	}
+	if st.Fastest != user {
+		return "", false
+	}
+	if st.Judgement != nil && st.Judgement.User == user {
+		// 判定待ちの回答は取り下げる
+		st.Judgement = nil
+	}
+	if len(st.BuzzOrder) > 0 {
+		st.Fastest = st.BuzzOrder[0]
+		return st.Fastest, true
+	}
+	st.Fastest = ""
+	return "", true
 }
 
 // Broadcast sends a message to all clients in the room except the sender.
 func (m *RoomManager) Broadcast(roomID string, sender *websocket.Conn, mt int, msg []byte) {
+	// タイマーからも送信されるため、接続一覧をコピーしてから書き込む
 	m.mu.RLock()
-	clients := m.rooms[roomID]
+	clients := make(map[*websocket.Conn]*sync.Mutex, len(m.rooms[roomID]))
+	for conn, wmu := range m.rooms[roomID] {
+		clients[conn] = wmu
+	}
 	m.mu.RUnlock()
 
-	for conn := range clients {
+	for conn, wmu := range clients {
 		if conn == sender {
 			continue
 		}
+		wmu.Lock()
 		conn.WriteMessage(mt, msg) // ignore errors for simplicity
+		wmu.Unlock()
+	}
+}
+
+// Send writes a message to a single connection in the room.
+func (m *RoomManager) Send(roomID string, conn *websocket.Conn, mt int, msg []byte) {
+	m.mu.RLock()
+	wmu := m.rooms[roomID][conn]
+	m.mu.RUnlock()
+	if wmu == nil {
+		return
+	}
+	wmu.Lock()
+	defer wmu.Unlock()
+	conn.WriteMessage(mt, msg) // ignore errors for simplicity
+}
+
+// SendToUser writes a message to every connection of user in the room.
+func (m *RoomManager) SendToUser(roomID, user string, mt int, msg []byte) {
+	m.mu.RLock()
+	var conns []*websocket.Conn
+	if st := m.states[roomID]; st != nil {
+		for conn, u := range st.Users {
+			if u == user {
+				conns = append(conns, conn)
+			}
+		}
+	}
+	m.mu.RUnlock()
+	for _, conn := range conns {
+		m.Send(roomID, conn, mt, msg)
 	}
 }
 
@@ -152,21 +386,58 @@ func (m *RoomManager) StartQuestion(roomID string) {
 	m.mu.Lock()
 	st, ok := m.states[roomID]
 	if !ok {
-		st = &RoomState{}
+		st = newRoomState()
 		m.states[roomID] = st
 	}
+	cancel := st.armQuestion()
+	m.mu.Unlock()
+	m.watchTimeout(roomID, cancel)
+}
+
+// HostStartQuestion starts the question on behalf of conn. Only the host may
+// start it, and only from the lobby or while waiting for the ready check.
+func (m *RoomManager) HostStartQuestion(roomID string, conn *websocket.Conn) error {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st == nil || !st.isHost(conn) {
+		m.mu.Unlock()
+		return ErrNotHost
+	}
+	if st.Phase != PhaseLobby && st.Phase != PhaseWaiting {
+		m.mu.Unlock()
+		return ErrCannotStart
+	}
+	cancel := st.armQuestion()
+	m.mu.Unlock()
+	m.watchTimeout(roomID, cancel)
+	return nil
+}
+
+// armQuestion switches the room to the playing phase and replaces the question
+// timer. It returns the channel that cancels the new timer. The caller must
+// hold the lock.
+func (st *RoomState) armQuestion() chan struct{} {
 	// 既存タイマーがあればキャンセル
 	if st.TimeoutCancel != nil {
 		close(st.TimeoutCancel)
 		st.TimeoutCancel = nil
 	}
 	st.TimeoutCancel = make(chan struct{})
+	if st.StartedAt.IsZero() {
+		st.StartedAt = time.Now()
+	}
+	st.setPhase(PhasePlaying)
+	st.PhaseDeadline = time.Now().Add(time.Duration(config.TimeLimit) * time.Second)
 	st.Active = true
 	st.Fastest = ""
 	st.BuzzOrder = nil
-	cancel := st.TimeoutCancel
-	m.mu.Unlock()
+	st.QuestionStart = time.Now()
+	st.BuzzTimes = make(map[string]time.Time)
+	return st.TimeoutCancel
+}
 
+// watchTimeout ends the question when nobody buzzes before the time limit.
+func (m *RoomManager) watchTimeout(roomID string, cancel chan struct{}) {
 	go func() {
 		select {
 		case <-time.After(time.Duration(config.TimeLimit) * time.Second):
@@ -177,13 +448,7 @@ func (m *RoomManager) StartQuestion(roomID string) {
 				m.mu.Unlock()
 				resp, _ := json.Marshal(&model.ServerMessage{Type: "timeout", Timestamp: time.Now().UnixMilli()})
 				m.Broadcast(roomID, nil, websocket.TextMessage, resp)
-				states := m.ResetReady(roomID)
-				readyMsg, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Timestamp: time.Now().UnixMilli()})
-				m.Broadcast(roomID, nil, websocket.TextMessage, readyMsg)
-				if vid, err := m.NextVideo(roomID); err == nil {
-					videoMsg, _ := json.Marshal(&model.ServerMessage{Type: "video", VideoID: vid, Timestamp: time.Now().UnixMilli()})
-					m.Broadcast(roomID, nil, websocket.TextMessage, videoMsg)
-				}
+				m.advance(roomID, OutcomeTimeout)
 				return
 			}
 			m.mu.Unlock()
@@ -222,6 +487,12 @@ func (m *RoomManager) AddBuzz(roomID, user string) (bool, []string) {
 		}
 	}
 	st.BuzzOrder = append(st.BuzzOrder, user)
+	if st.BuzzTimes != nil {
+		st.BuzzTimes[user] = time.Now()
+	}
+	if q := st.currentQuestion(); q != nil {
+		q.BuzzOrder = append(q.BuzzOrder, user)
+	}
 	first := false
 	if st.Active && st.Fastest == "" {
 		st.Fastest = user
@@ -238,30 +509,296 @@ func (m *RoomManager) SetVideoTitle(roomID, title string) {
 	defer m.mu.Unlock()
 	st := m.states[roomID]
 	if st == nil {
-		st = &RoomState{Ready: make(map[string]bool), Users: make(map[*websocket.Conn]string)}
+		st = newRoomState()
 		m.states[roomID] = st
 	}
 	st.VideoTitle = title
+	st.VideoAnswers = nil
 }
 
-// SetPlaylist stores the playlist ID for the room.
-func (m *RoomManager) SetPlaylist(roomID, playlistID string) error {
+// SetPlaylist stores the playlist ID for the room and starts a new game with
+// it in the lobby. The reset ready states are returned.
+func (m *RoomManager) SetPlaylist(roomID, playlistID string) (map[string]bool, error) {
+	if m.dailyDate(roomID) != "" {
+		return nil, ErrDailyPlaylistFixed
+	}
+	// プレイリストの取得は API を呼ぶのでロックの外で行う
+	videos, err := m.loadVideos("", playlistID)
+	if err != nil {
+		return nil, err
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	st := m.states[roomID]
 	if st == nil {
-		st = &RoomState{Ready: make(map[string]bool), Users: make(map[*websocket.Conn]string)}
+		st = newRoomState()
 		m.states[roomID] = st
 	}
+	if st.DailyDate != "" {
+		return nil, ErrDailyPlaylistFixed
+	}
 	st.PlaylistID = playlistID
-	apiKey := os.Getenv("YOUTUBE_API_KEY")
-	yt := NewYouTubeService(apiKey)
-	videos, err := yt.ListPlaylistVideos(playlistID)
-	if err != nil {
-		return err
+	st.PlayedVideos = nil
+	st.resetGame(videos)
+	return copyReady(st.Ready), nil
+}
+
+// resetGame stops the running question and clears rounds, scores, ready and
+// buzz state so a new game starts from the lobby with videos as its pool. The
+// caller must hold the lock.
+func (st *RoomState) resetGame(videos []VideoItem) {
+	if st.TimeoutCancel != nil {
+		close(st.TimeoutCancel)
+		st.TimeoutCancel = nil
 	}
 	st.RemainingVideos = videos
-	return nil
+	st.Active = false
+	st.Fastest = ""
+	st.BuzzOrder = nil
+	st.VideoID = ""
+	st.VideoTitle = ""
+	st.VideoAnswers = nil
+	st.Round = 0
+	st.Tiebreak = nil
+	st.Questions = nil
+	st.Judgement = nil
+	st.Rejections = nil
+	st.Appeal = nil
+	st.GameOver = false
+	st.StartedAt = time.Time{}
+	for u := range st.Scores {
+		st.Scores[u] = 0
+	}
+	for u := range st.Ready {
+		st.Ready[u] = false
+	}
+	st.setPhase(PhaseLobby)
+}
+
+// Rematch resets scores, rounds and buzz state so the same players can play the
+// stored playlist again. Only the host's connection may request a rematch. When excludePlayed
+// is set, videos already played in this room are left out of the new pool.
+// The room returns to the lobby phase and the reset ready states are returned.
+func (m *RoomManager) Rematch(roomID string, conn *websocket.Conn, excludePlayed bool) (map[string]bool, error) {
+	m.mu.RLock()
+	st := m.states[roomID]
+	if st == nil || st.PlaylistID == "" {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("playlist not set")
+	}
+	if !st.isHost(conn) {
+		m.mu.RUnlock()
+		return nil, ErrNotHost
+	}
+	date, playlistID := st.DailyDate, st.PlaylistID
+	m.mu.RUnlock()
+	if date != "" {
+		// 日付が変わっていればその日のチャレンジに切り替える
+		date = DailyDate(time.Now())
+	}
+	videos, err := m.loadVideos(date, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states[roomID] != st || !st.isHost(conn) {
+		return nil, ErrNotHost
+	}
+	if st.PlaylistID != playlistID {
+		return nil, fmt.Errorf("playlist changed")
+	}
+	if st.DailyDate != "" {
+		st.DailyDate = date
+	}
+	if excludePlayed {
+		played := make(map[string]bool, len(st.PlayedVideos))
+		for _, id := range st.PlayedVideos {
+			played[id] = true
+		}
+		var rest []VideoItem
+		for _, v := range videos {
+			if !played[v.ID] {
+				rest = append(rest, v)
+			}
+		}
+		if len(rest) == 0 {
+			return nil, fmt.Errorf("no unplayed videos left")
+		}
+		videos = rest
+	} else {
+		st.PlayedVideos = nil
+	}
+	st.resetGame(videos)
+	return copyReady(st.Ready), nil
+}
+
+// FinishGame ends the game once the configured number of questions has been
+// played and returns the final standings. It reports false while the game is
+// still running.
+func (m *RoomManager) FinishGame(roomID string) ([]model.Standing, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.states[roomID]
+	if st == nil || st.GameOver || st.Round < config.QuestionCount {
+		return nil, false
+	}
+	if st.Tiebreak != nil && !st.Tiebreak.done() {
+		return nil, false
+	}
+	st.setPhase(PhaseFinished)
+	st.GameOver = true
+	st.Active = false
+	st.Fastest = ""
+	st.BuzzOrder = nil
+	winner := ""
+	if st.Tiebreak != nil {
+		winner = st.Tiebreak.Winner
+	}
+	return standings(st.Scores, winner), true
+}
+
+// standings orders players by score and assigns ranks, sharing a rank on ties.
+// A tiebreak winner is placed alone in first place ahead of the other leaders.
+func standings(scores map[string]int, tiebreakWinner string) []model.Standing {
+	list := make([]model.Standing, 0, len(scores))
+	for u, sc := range scores {
+		list = append(list, model.Standing{User: u, Score: sc})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		if list[i].User == tiebreakWinner || list[j].User == tiebreakWinner {
+			return list[i].User == tiebreakWinner
+		}
+		return list[i].User < list[j].User
+	})
+	for i := range list {
+		if i > 0 && list[i].Score == list[i-1].Score && list[i-1].User != tiebreakWinner {
+			list[i].Rank = list[i-1].Rank
+		} else {
+			list[i].Rank = i + 1
+		}
+	}
+	return list
+}
+
+// matchRecord builds the history record of the room's finished game.
+func (m *RoomManager) matchRecord(roomID string, final []model.Standing) *MatchRecord {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rec := &MatchRecord{RoomID: roomID, Standings: final, EndedAt: time.Now()}
+	for _, s := range final {
+		rec.Players = append(rec.Players, s.User)
+	}
+	sort.Strings(rec.Players)
+	st := m.states[roomID]
+	if st == nil {
+		return rec
+	}
+	rec.PlaylistID = st.PlaylistID
+	if st.Tiebreak != nil {
+		rec.Tiebreak = st.Tiebreak.result()
+	}
+	rec.StartedAt = st.StartedAt
+	rec.Questions = append([]QuestionRecord(nil), st.Questions...)
+	return rec
+}
+
+// advance reveals the answer of a resolved question and, once the reveal has
+// been shown for the configured duration, moves on to the next question.
+func (m *RoomManager) advance(roomID, outcome string) {
+	reveal, ok := m.Reveal(roomID, outcome)
+	if !ok {
+		return
+	}
+	d := time.Duration(config.RevealDuration) * time.Second
+	reveal.Deadline = time.Now().Add(d).UnixMilli()
+	msg, _ := json.Marshal(reveal)
+	m.Broadcast(roomID, nil, websocket.TextMessage, msg)
+	m.schedulePhase(roomID, PhaseReveal, d, func() {
+		m.proceed(roomID)
+	})
+}
+
+// proceed ends the game when the last question was played, otherwise it resets
+// ready states, sends the next video and starts the intermission.
+func (m *RoomManager) proceed(roomID string) {
+	if wait := m.appealRemaining(roomID); wait > 0 {
+		// 判定が覆ると順位が変わるため、異議の投票が終わるまで待つ
+		m.schedulePhase(roomID, PhaseReveal, wait, func() {
+			m.proceed(roomID)
+		})
+		return
+	}
+	if tied, ok := m.StartTiebreak(roomID); ok {
+		msg, _ := json.Marshal(&model.ServerMessage{Type: "tiebreak", Tiebreak: &model.TiebreakResult{Players: tied}, Timestamp: time.Now().UnixMilli()})
+		m.Broadcast(roomID, nil, websocket.TextMessage, msg)
+	}
+	if final, over := m.FinishGame(roomID); over {
+		ratings := m.ratings.Update(final)
+		rec := m.matchRecord(roomID, final)
+		if err := m.history.Save(rec); err != nil {
+			log.Printf("save match: %v", err)
+		}
+		if date := m.dailyDate(roomID); date != "" {
+			m.daily.Record(date, final)
+		}
+		msg, _ := json.Marshal(&model.ServerMessage{Type: "game_over", Standings: final, Scores: m.GetScores(roomID), Ratings: ratings, MatchID: rec.ID, Tiebreak: rec.Tiebreak, Phase: PhaseFinished, Timestamp: time.Now().UnixMilli()})
+		m.Broadcast(roomID, nil, websocket.TextMessage, msg)
+		return
+	}
+	states := m.ResetReady(roomID)
+	readyMsg, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Host: m.GetHost(roomID), Ratings: m.GetRatings(roomID), Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, readyMsg)
+	if vid, err := m.NextVideo(roomID); err == nil {
+		videoMsg, _ := json.Marshal(m.videoMessage(roomID, vid))
+		m.Broadcast(roomID, nil, websocket.TextMessage, videoMsg)
+	}
+	m.schedulePhase(roomID, PhaseIntermission, time.Duration(config.IntermissionDuration)*time.Second, func() {
+		m.endIntermission(roomID)
+	})
+}
+
+// GetRatings returns the ratings of the users currently in the room.
+func (m *RoomManager) GetRatings(roomID string) map[string]int {
+	m.mu.RLock()
+	st := m.states[roomID]
+	var users []string
+	if st != nil {
+		for _, u := range st.Users {
+			users = append(users, u)
+		}
+	}
+	m.mu.RUnlock()
+	if len(users) == 0 {
+		return nil
+	}
+	return m.ratings.Lookup(users)
+}
+
+// GetScores returns a copy of the room's scores.
+func (m *RoomManager) GetScores(roomID string) map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil {
+		return nil
+	}
+	return copyScores(st.Scores)
+}
+
+// GetHost returns the host of the room.
+func (m *RoomManager) GetHost(roomID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil {
+		return ""
+	}
+	return st.Host
 }
 
 // GetVideoTitle retrieves the stored video title.
@@ -275,57 +812,195 @@ func (m *RoomManager) GetVideoTitle(roomID string) string {
 	return st.VideoTitle
 }
 
-// NextVideo retrieves a random video using the stored playlist ID.
-func (m *RoomManager) NextVideo(roomID string) (string, error) {
+// loadVideos fetches the full video pool of a room: the daily challenge set
+// for daily rooms, otherwise the stored playlist or question pack. It may call
+// the YouTube API, so it must be called without holding m.mu.
+func (m *RoomManager) loadVideos(dailyDate, playlistID string) ([]VideoItem, error) {
+	if dailyDate != "" {
+		return m.daily.Videos(dailyDate)
+	}
+	return m.source.Videos(context.Background(), playlistID)
+}
+
+// LoadDaily turns the room into a daily challenge room. It reports true when
+// the daily track list was loaded by this call and the first video should be sent.
+func (m *RoomManager) LoadDaily(roomID string) (bool, error) {
+	if m.dailyDate(roomID) != "" {
+		return false, nil
+	}
+	date := DailyDate(time.Now())
+	videos, err := m.daily.Videos(date)
+	if err != nil {
+		return false, err
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	st := m.states[roomID]
+	if st == nil {
+		st = newRoomState()
+		m.states[roomID] = st
+	}
+	if st.DailyDate != "" {
+		return false, nil
+	}
+	st.DailyDate = date
+	st.PlaylistID = config.DailyPlaylistID
+	st.RemainingVideos = videos
+	st.PlayedVideos = nil
+	return true, nil
+}
+
+// dailyDate returns the daily challenge date the room is playing, if any.
+func (m *RoomManager) dailyDate(roomID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if st := m.states[roomID]; st != nil {
+		return st.DailyDate
+	}
+	return ""
+}
+
+// videoMessage builds the "video" message for the room's current video.
+func (m *RoomManager) videoMessage(roomID, videoID string) *model.ServerMessage {
+	msg := &model.ServerMessage{Type: "video", VideoID: videoID, Timestamp: time.Now().UnixMilli()}
+	m.mu.RLock()
+	if st := m.states[roomID]; st != nil && st.VideoID == videoID {
+		msg.StartSeconds = st.VideoStart
+	}
+	m.mu.RUnlock()
+	return msg
+}
+
+// NextVideo retrieves a random video using the stored playlist ID.
+func (m *RoomManager) NextVideo(roomID string) (string, error) {
+	m.mu.RLock()
+	st := m.states[roomID]
 	if st == nil || st.PlaylistID == "" {
+		m.mu.RUnlock()
 		return "", fmt.Errorf("playlist not set")
 	}
+	var reload []VideoItem
 	if len(st.RemainingVideos) == 0 {
-		apiKey := os.Getenv("YOUTUBE_API_KEY")
-		yt := NewYouTubeService(apiKey)
-		vids, err := yt.ListPlaylistVideos(st.PlaylistID)
+		date, playlistID := st.DailyDate, st.PlaylistID
+		m.mu.RUnlock()
+		vids, err := m.loadVideos(date, playlistID)
 		if err != nil {
 			return "", err
 		}
-		st.RemainingVideos = vids
+		reload = vids
+	} else {
+		m.mu.RUnlock()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st = m.states[roomID]
+	if st == nil || st.PlaylistID == "" {
+		return "", fmt.Errorf("playlist not set")
+	}
+	if len(st.RemainingVideos) == 0 {
+		st.RemainingVideos = reload
 	}
 	if len(st.RemainingVideos) == 0 {
 		return "", fmt.Errorf("no videos available")
 	}
+	// デイリーチャレンジは決められた順番で出題する
+	item, rest, err := popVideo(st.RemainingVideos, st.DailyDate == "")
+	st.RemainingVideos = rest
+	if err != nil {
+		return "", err
+	}
+	st.VideoID = item.ID
+	st.VideoTitle = item.Title
+	st.VideoChannel = item.Channel
+	st.VideoStart = item.Start
+	st.VideoAnswers = m.aliases.acceptedAnswers(item)
+	st.PlayedVideos = append(st.PlayedVideos, item.ID)
+	st.Round++
+	if st.Tiebreak != nil {
+		st.Tiebreak.Questions++
+	}
+	st.Questions = append(st.Questions, QuestionRecord{Round: st.Round, VideoID: item.ID, VideoTitle: item.Title, RawTitle: item.RawTitle, Channel: item.Channel})
+	return item.ID, nil
+}
+
+// popVideo takes a video from list and returns it with the remaining list. The
+// video is taken at random when shuffle is set, otherwise from the front.
+// Embeddability is checked when the playlist is loaded, so every video in the
+// list is playable.
+func popVideo(list []VideoItem, shuffle bool) (VideoItem, []VideoItem, error) {
+	if len(list) == 0 {
+		return VideoItem{}, list, fmt.Errorf("no videos left")
+	}
 	// Go 1.20以降はrand.Seedでの初期化は不要です
-	for len(st.RemainingVideos) > 0 {
-		idx := rand.Intn(len(st.RemainingVideos))
-		item := st.RemainingVideos[idx]
-		st.RemainingVideos = append(st.RemainingVideos[:idx], st.RemainingVideos[idx+1:]...)
-		emb, err := CheckEmbeddable(item.ID)
-		if err != nil || !emb {
-			continue
-		}
-		st.VideoTitle = item.Title
-		return item.ID, nil
+	idx := 0
+	if shuffle {
+		idx = rand.Intn(len(list))
 	}
-	return "", fmt.Errorf("no embeddable videos found")
+	item := list[idx]
+	return item, append(list[:idx], list[idx+1:]...), nil
 }
 
-// SubmitAnswer checks the user's answer and advances to the next if incorrect.
-func (m *RoomManager) SubmitAnswer(roomID, user, answer string) (bool, string) {
+// SubmitAnswer checks the user's answer against the title and its aliases with
+// the room's judge and advances to the next if incorrect. It returns the verdict
+// and the next user to answer.
+func (m *RoomManager) SubmitAnswer(roomID, user, answer string) (Verdict, string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	st := m.states[roomID]
 	if st == nil {
-		return false, ""
+		return Verdict{}, ""
+	}
+	verdict := judgeFor(st.Settings).Judge(st.acceptedAnswers(), answer)
+	return verdict, st.applyVerdict(user, answer, verdict)
+}
+
+// acceptedAnswers returns the answers accepted for the current question.
+func (st *RoomState) acceptedAnswers() []string {
+	if len(st.VideoAnswers) == 0 {
+		return []string{st.VideoTitle}
+	}
+	return st.VideoAnswers
+}
+
+// applyVerdict records the judged answer of user and updates scores and the
+// buzz order. It returns the next user allowed to answer, if any. The caller
+// must hold the lock.
+func (st *RoomState) applyVerdict(user, answer string, verdict Verdict) string {
+	correct := verdict.Correct
+	if q := st.currentQuestion(); q != nil {
+		q.Answers = append(q.Answers, AnswerRecord{User: user, Answer: answer, Correct: correct, Matched: verdict.Matched, Confidence: verdict.Confidence})
+		if correct {
+			q.AnsweredBy = user
+			if st.Tiebreak == nil {
+				q.Points = pointsPerCorrect
+			}
+			if t, ok := st.BuzzTimes[user]; ok {
+				q.ReactionMs = t.Sub(st.QuestionStart).Milliseconds()
+			}
+		}
+	}
+	if st.Tiebreak != nil {
+		// サドンデスでは得点を加えず、正解者が勝者・誤答者は脱落
+		if correct {
+			st.Tiebreak.Winner = user
+		} else {
+			st.Tiebreak.eliminate(user)
+			if st.Tiebreak.Winner != "" {
+				st.BuzzOrder = nil
+			}
+		}
+	} else if correct {
+		st.Scores[user] += pointsPerCorrect
 	}
-	// 正解判定を「タイトルに含まれていれば正解」に変更
-	title := strings.ToLower(strings.TrimSpace(st.VideoTitle))
-	ans := strings.ToLower(strings.TrimSpace(answer))
-	if title != "" && ans != "" && strings.Contains(title, ans) {
+	if !correct {
+		st.recordRejection(user, answer)
+	}
+	if correct {
 		st.Active = false
 		st.Fastest = ""
 		st.BuzzOrder = nil
-		return true, ""
+		return ""
 	}
 	// remove user from buzz order
 	if len(st.BuzzOrder) > 0 {
@@ -342,10 +1017,47 @@ func (m *RoomManager) SubmitAnswer(roomID, user, answer string) (bool, string) {
 	}
 	if len(st.BuzzOrder) > 0 {
 		st.Fastest = st.BuzzOrder[0]
-		return false, st.Fastest
+		return st.Fastest
 	}
 	st.Fastest = ""
-	return false, ""
+	return ""
+}
+
+// announceVerdict broadcasts the result of an answer and moves on: a correct
+// answer ends the question, a wrong one passes the answer right to next or, when
+// nobody is left, ends the question as incorrect.
+func (m *RoomManager) announceVerdict(roomID, user string, verdict Verdict, next string) {
+	result := &model.ServerMessage{Type: "answer_result", User: user, Correct: verdict.Correct, Timestamp: time.Now().UnixMilli()}
+	if verdict.Correct {
+		// 不正解時にタイトルが漏れないよう正解時のみ含める
+		result.VideoTitle = m.GetVideoTitle(roomID)
+		result.MatchedAlias = verdict.Matched
+		result.Confidence = verdict.Confidence
+	} else if until := m.appealDeadline(roomID, user); !until.IsZero() {
+		result.Deadline = until.UnixMilli()
+	}
+	resultMsg, _ := json.Marshal(result)
+	m.Broadcast(roomID, nil, websocket.TextMessage, resultMsg)
+	if !verdict.Correct && next != "" {
+		nextMsg, _ := json.Marshal(&model.ServerMessage{Type: "buzz_result", User: next, Timestamp: time.Now().UnixMilli()})
+		m.Broadcast(roomID, nil, websocket.TextMessage, nextMsg)
+	}
+	if verdict.Correct {
+		m.advance(roomID, OutcomeCorrect)
+	} else if next == "" {
+		m.advance(roomID, OutcomeIncorrect)
+	}
+}
+
+// IsAnswering reports whether user currently holds the right to answer.
+func (m *RoomManager) IsAnswering(roomID, user string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil {
+		return false
+	}
+	return st.Phase == PhasePlaying && st.Fastest != "" && st.Fastest == user
 }
 
 // IsActive returns whether a question is active.
@@ -381,35 +1093,98 @@ func (r *RoomService) ProcessMessage(mt int, msg []byte) (int, []byte) {
 	switch req.Type {
 	case "join":
 		states := r.manager.RegisterUser(r.roomID, r.conn, req.User)
-		resp, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Timestamp: time.Now().UnixMilli()})
-		r.conn.WriteMessage(websocket.TextMessage, resp)
+		resp, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Host: r.manager.GetHost(r.roomID), Ratings: r.manager.GetRatings(r.roomID), Timestamp: time.Now().UnixMilli()})
+		r.manager.Send(r.roomID, r.conn, websocket.TextMessage, resp)
 		r.manager.Broadcast(r.roomID, r.conn, websocket.TextMessage, resp)
+		if IsDailyRoom(r.roomID) {
+			loaded, err := r.manager.LoadDaily(r.roomID)
+			if err != nil {
+				log.Printf("load daily challenge: %v", err)
+				break
+			}
+			if !loaded {
+				break
+			}
+			if vid, err := r.manager.NextVideo(r.roomID); err == nil {
+				videoMsg, _ := json.Marshal(r.manager.videoMessage(r.roomID, vid))
+				r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, videoMsg)
+			}
+		}
 	case "playlist":
-		if err := r.manager.SetPlaylist(r.roomID, req.PlaylistID); err != nil {
+		states, err := r.manager.SetPlaylist(r.roomID, req.PlaylistID)
+		if err != nil {
 			break
 		}
+		// 新しいプレイリストで最初からやり直すので準備状態とスコアも配り直す
+		reset, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Host: r.manager.GetHost(r.roomID), Ratings: r.manager.GetRatings(r.roomID), Phase: PhaseLobby, Scores: r.manager.GetScores(r.roomID), Timestamp: time.Now().UnixMilli()})
+		r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, reset)
+		if r.manager.YouTube().Quota.Exhausted() {
+			// クォータ切れの間はキャッシュかローカルのパックから出題していることを知らせる
+			notice, _ := json.Marshal(&model.ServerMessage{Type: "quota_exhausted", Reason: ErrQuotaExhausted.Error(), Timestamp: time.Now().UnixMilli()})
+			r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, notice)
+		}
 		videoID, err := r.manager.NextVideo(r.roomID)
 		if err != nil {
 			break
 		}
-		resp, _ := json.Marshal(&model.ServerMessage{Type: "video", VideoID: videoID, Timestamp: time.Now().UnixMilli()})
-		r.conn.WriteMessage(websocket.TextMessage, resp)
+		resp, _ := json.Marshal(r.manager.videoMessage(r.roomID, videoID))
+		r.manager.Send(r.roomID, r.conn, websocket.TextMessage, resp)
 		r.manager.Broadcast(r.roomID, r.conn, websocket.TextMessage, resp)
 	case "ready":
 		all, states := r.manager.SetReady(r.roomID, req.User)
-		resp, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Timestamp: time.Now().UnixMilli()})
-		r.conn.WriteMessage(websocket.TextMessage, resp)
+		resp, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Host: r.manager.GetHost(r.roomID), Ratings: r.manager.GetRatings(r.roomID), Timestamp: time.Now().UnixMilli()})
+		r.manager.Send(r.roomID, r.conn, websocket.TextMessage, resp)
 		r.manager.Broadcast(r.roomID, r.conn, websocket.TextMessage, resp)
 		if all {
-			r.manager.StartQuestion(r.roomID)
-			startMsg, _ := json.Marshal(&model.ServerMessage{Type: "start", Timestamp: time.Now().UnixMilli()})
-			r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, startMsg)
+			r.manager.beginQuestion(r.roomID)
 		}
-	case "start":
-		r.manager.StartQuestion(r.roomID)
-		resp, _ := json.Marshal(&model.ServerMessage{Type: "start", Timestamp: time.Now().UnixMilli()})
+	case "rematch":
+		states, err := r.manager.Rematch(r.roomID, r.conn, req.ExcludePlayed)
+		if err != nil {
+			break
+		}
+		resp, _ := json.Marshal(&model.ServerMessage{Type: "rematch", ReadyUsers: states, Host: r.manager.GetHost(r.roomID), Ratings: r.manager.GetRatings(r.roomID), Phase: PhaseLobby, Scores: r.manager.GetScores(r.roomID), Timestamp: time.Now().UnixMilli()})
 		r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, resp)
+		if vid, err := r.manager.NextVideo(r.roomID); err == nil {
+			videoMsg, _ := json.Marshal(r.manager.videoMessage(r.roomID, vid))
+			r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, videoMsg)
+		}
+	case "chat":
+		user, text, err := r.manager.PostChat(r.roomID, r.conn, req.Text)
+		if err != nil {
+			resp, _ := json.Marshal(&model.ServerMessage{Type: "chat_rejected", Reason: err.Error(), Timestamp: time.Now().UnixMilli()})
+			r.manager.Send(r.roomID, r.conn, websocket.TextMessage, resp)
+			break
+		}
+		resp, _ := json.Marshal(&model.ServerMessage{Type: "chat", User: user, Text: text, Timestamp: time.Now().UnixMilli()})
+		r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, resp)
+	case "reaction":
+		// 集計して一定間隔で "reactions" としてまとめて配信する
+		r.manager.AddReaction(r.roomID, r.conn, req.Emoji)
+	case "skip":
+		if err := r.manager.SkipQuestion(r.roomID, r.conn); err != nil {
+			break
+		}
+		r.manager.advance(r.roomID, OutcomeSkip)
+	case "settings":
+		if req.Settings == nil {
+			break
+		}
+		settings, err := r.manager.UpdateSettings(r.roomID, r.conn, *req.Settings)
+		if err != nil {
+			break
+		}
+		resp, _ := json.Marshal(&model.ServerMessage{Type: "settings", Settings: &settings, Timestamp: time.Now().UnixMilli()})
+		r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, resp)
+	case "start":
+		if err := r.manager.HostStartQuestion(r.roomID, r.conn); err != nil {
+			break
+		}
+		r.manager.announceQuestion(r.roomID)
 	case "buzz":
+		if !r.manager.CanBuzz(r.roomID, req.User) {
+			break
+		}
 		// broadcast that someone pressed the answer button
 		note, _ := json.Marshal(&model.ServerMessage{Type: "answer", User: req.User, Timestamp: time.Now().UnixMilli()})
 		r.manager.Broadcast(r.roomID, r.conn, websocket.TextMessage, note)
@@ -423,22 +1198,20 @@ func (r *RoomService) ProcessMessage(mt int, msg []byte) (int, []byte) {
 			r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, resp)
 		}
 	case "answer_text":
-		correct, next := r.manager.SubmitAnswer(r.roomID, req.User, req.Answer)
-		resultMsg, _ := json.Marshal(&model.ServerMessage{Type: "answer_result", User: req.User, Correct: correct, VideoTitle: r.manager.GetVideoTitle(r.roomID), Timestamp: time.Now().UnixMilli()})
-		r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, resultMsg)
-		if !correct && next != "" {
-			nextMsg, _ := json.Marshal(&model.ServerMessage{Type: "buzz_result", User: next, Timestamp: time.Now().UnixMilli()})
-			r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, nextMsg)
-		}
-		if correct || next == "" {
-			states := r.manager.ResetReady(r.roomID)
-			stateMsg, _ := json.Marshal(&model.ServerMessage{Type: "ready_state", ReadyUsers: states, Timestamp: time.Now().UnixMilli()})
-			r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, stateMsg)
-			if vid, err := r.manager.NextVideo(r.roomID); err == nil {
-				videoMsg, _ := json.Marshal(&model.ServerMessage{Type: "video", VideoID: vid, Timestamp: time.Now().UnixMilli()})
-				r.manager.Broadcast(r.roomID, nil, websocket.TextMessage, videoMsg)
-			}
+		if !r.manager.IsAnswering(r.roomID, req.User) {
+			break
+		}
+		if r.manager.requestJudgement(r.roomID, req.User, req.Answer) {
+			break
 		}
+		verdict, next := r.manager.SubmitAnswer(r.roomID, req.User, req.Answer)
+		r.manager.announceVerdict(r.roomID, req.User, verdict, next)
+	case "judge":
+		r.manager.JudgeAnswer(r.roomID, r.conn, req.Accept)
+	case "appeal":
+		r.manager.FileAppeal(r.roomID, r.conn)
+	case "appeal_vote":
+		r.manager.VoteAppeal(r.roomID, r.conn, req.Accept)
 	}
 
 	return 0, nil
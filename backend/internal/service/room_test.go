@@ -0,0 +1,164 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// testVideos is the playlist "PL1" served to rooms opened by testRoom.
+var testVideos = []VideoItem{
+	{ID: "v1", Title: "Pretender"},
+	{ID: "v2", Title: "Lemon"},
+	{ID: "v3", Title: "紅蓮華"},
+}
+
+// setConfig sets an integer setting for the duration of the test.
+func setConfig(t *testing.T, p *int, v int) {
+	t.Helper()
+	old := *p
+	*p = v
+	t.Cleanup(func() { *p = old })
+}
+
+// testRoom opens roomID with one connection per player, registered in order so
+// that the first player is the host, and picks the playlist "PL1". Timers are
+// set long enough not to fire during a test.
+func testRoom(t *testing.T, roomID string, players ...string) (*RoomManager, []*websocket.Conn) {
+	t.Helper()
+	useDataDir(t)
+	setConfig(t, &config.TimeLimit, 60)
+	setConfig(t, &config.RevealDuration, 60)
+	setConfig(t, &config.JudgeTimeout, 60)
+	setConfig(t, &config.AppealWindow, 60)
+
+	f := &fakeYouTube{playlist: testVideos, videos: make(map[string]map[string]any)}
+	for _, v := range testVideos {
+		f.videos[v.ID] = embeddableVideo(v.ID)
+	}
+	yt := newFakeYouTube(t, f)
+	m := NewRoomManager()
+	m.youtube.HTTPClient, m.youtube.BaseURL, m.youtube.APIKey = yt.HTTPClient, yt.BaseURL, yt.APIKey
+
+	accepted := make(chan *websocket.Conn)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		accepted <- c
+	}))
+	t.Cleanup(srv.Close)
+
+	var conns []*websocket.Conn
+	for _, name := range players {
+		client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { client.Close() })
+		go func() {
+			// 配信されたメッセージは読み捨てる
+			for {
+				if _, _, err := client.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		conn := <-accepted
+		t.Cleanup(func() { conn.Close() })
+		m.Join(roomID, conn)
+		m.RegisterUser(roomID, conn, name)
+		conns = append(conns, conn)
+	}
+	if _, err := m.SetPlaylist(roomID, "PL1"); err != nil {
+		t.Fatal(err)
+	}
+	return m, conns
+}
+
+func TestStandings(t *testing.T) {
+	scores := map[string]int{"alice": 3, "bob": 3, "carol": 1}
+	tests := []struct {
+		name   string
+		winner string
+		want   []model.Standing
+	}{
+		{"shared first place", "", []model.Standing{
+			{User: "alice", Score: 3, Rank: 1},
+			{User: "bob", Score: 3, Rank: 1},
+			{User: "carol", Score: 1, Rank: 3},
+		}},
+		{"tiebreak winner", "bob", []model.Standing{
+			{User: "bob", Score: 3, Rank: 1},
+			{User: "alice", Score: 3, Rank: 2},
+			{User: "carol", Score: 1, Rank: 3},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := standings(scores, tt.winner); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("standings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRematch(t *testing.T) {
+	m, conns := testRoom(t, "r1", "alice", "bob")
+	for i := 0; i < 2; i++ {
+		if _, err := m.NextVideo("r1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := m.states["r1"]
+	played := append([]string(nil), st.PlayedVideos...)
+	st.Scores["alice"], st.Scores["bob"] = 2, 1
+	st.Ready["alice"], st.Ready["bob"] = true, true
+	st.setPhase(PhaseFinished)
+	st.GameOver = true
+
+	if _, err := m.Rematch("r1", conns[1], false); err != ErrNotHost {
+		t.Fatalf("Rematch() by bob = %v, want ErrNotHost", err)
+	}
+	if st.Round != 2 || !st.GameOver {
+		t.Fatal("Rematch() by a non-host changed the room")
+	}
+
+	ready, err := m.Rematch("r1", conns[0], true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Phase != PhaseLobby || st.Round != 0 || st.GameOver || len(st.Questions) != 0 {
+		t.Errorf("after Rematch() phase %q, round %d, game over %v, %d questions; want a fresh lobby", st.Phase, st.Round, st.GameOver, len(st.Questions))
+	}
+	if want := map[string]int{"alice": 0, "bob": 0}; !reflect.DeepEqual(st.Scores, want) {
+		t.Errorf("Scores = %v, want %v", st.Scores, want)
+	}
+	if want := map[string]bool{"alice": false, "bob": false}; !reflect.DeepEqual(ready, want) {
+		t.Errorf("Rematch() ready = %v, want %v", ready, want)
+	}
+	if len(st.RemainingVideos) != 1 {
+		t.Fatalf("RemainingVideos = %+v, want only the unplayed video", st.RemainingVideos)
+	}
+	for _, id := range played {
+		if st.RemainingVideos[0].ID == id {
+			t.Errorf("excludePlayed kept played video %s", id)
+		}
+	}
+
+	if _, err := m.Rematch("r1", conns[0], false); err != nil {
+		t.Fatal(err)
+	}
+	if len(st.RemainingVideos) != len(testVideos) || st.PlayedVideos != nil {
+		t.Errorf("full Rematch() left %d videos and played %v", len(st.RemainingVideos), st.PlayedVideos)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// setPhase moves the room into phase and invalidates pending phase timers.
+func (st *RoomState) setPhase(phase string) {
+	st.Phase = phase
+	st.PhaseDeadline = time.Time{}
+	st.PhaseGen++
+}
+
+// allReady reports whether every user in the room is ready.
+func (st *RoomState) allReady() bool {
+	if len(st.Ready) == 0 {
+		return false
+	}
+	for _, v := range st.Ready {
+		if !v {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcastPhase announces a phase transition and its deadline to the room.
+func (m *RoomManager) broadcastPhase(roomID, phase string, deadline time.Time) {
+	msg := &model.ServerMessage{Type: "phase", Phase: phase, Timestamp: time.Now().UnixMilli()}
+	if !deadline.IsZero() {
+		msg.Deadline = deadline.UnixMilli()
+	}
+	resp, _ := json.Marshal(msg)
+	m.Broadcast(roomID, nil, websocket.TextMessage, resp)
+}
+
+// schedulePhase moves the room into phase for d and runs next when the phase
+// ends. next is skipped if the room has changed phase in the meantime or was
+// closed and opened again under the same ID.
+func (m *RoomManager) schedulePhase(roomID, phase string, d time.Duration, next func()) {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st == nil {
+		m.mu.Unlock()
+		return
+	}
+	st.setPhase(phase)
+	st.PhaseDeadline = time.Now().Add(d)
+	gen := st.PhaseGen
+	deadline := st.PhaseDeadline
+	m.mu.Unlock()
+
+	m.broadcastPhase(roomID, phase, deadline)
+	time.AfterFunc(d, func() {
+		m.mu.RLock()
+		current := m.states[roomID] == st && st.PhaseGen == gen
+		m.mu.RUnlock()
+		if current {
+			next()
+		}
+	})
+}
+
+// endIntermission starts the next question when the room auto-advances or
+// everyone is already ready, otherwise it waits for the ready check.
+func (m *RoomManager) endIntermission(roomID string) {
+	m.mu.Lock()
+	st := m.states[roomID]
+	if st == nil || st.Phase != PhaseIntermission {
+		m.mu.Unlock()
+		return
+	}
+	start := st.Settings.AutoAdvance || st.allReady()
+	if !start {
+		st.setPhase(PhaseWaiting)
+	}
+	m.mu.Unlock()
+
+	if start {
+		m.beginQuestion(roomID)
+		return
+	}
+	m.broadcastPhase(roomID, PhaseWaiting, time.Time{})
+}
+
+// beginQuestion starts the question timer and notifies the room.
+func (m *RoomManager) beginQuestion(roomID string) {
+	m.StartQuestion(roomID)
+	m.announceQuestion(roomID)
+}
+
+// announceQuestion tells the room that the question has started.
+func (m *RoomManager) announceQuestion(roomID string) {
+	m.mu.RLock()
+	var deadline time.Time
+	if st := m.states[roomID]; st != nil {
+		deadline = st.PhaseDeadline
+	}
+	m.mu.RUnlock()
+	startMsg, _ := json.Marshal(&model.ServerMessage{Type: "start", Deadline: deadline.UnixMilli(), Timestamp: time.Now().UnixMilli()})
+	m.Broadcast(roomID, nil, websocket.TextMessage, startMsg)
+	m.broadcastPhase(roomID, PhasePlaying, deadline)
+}
+
+// UpdateSettings replaces the room settings. Only the host may change them.
+func (m *RoomManager) UpdateSettings(roomID string, conn *websocket.Conn, settings model.RoomSettings) (model.RoomSettings, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.states[roomID]
+	if st == nil || !st.isHost(conn) {
+		return model.RoomSettings{}, ErrNotHost
+	}
+	if !ValidStrictness(settings.Strictness) {
+		return model.RoomSettings{}, ErrInvalidStrictness
+	}
+	if !ValidJudge(settings.Judge) {
+		return model.RoomSettings{}, ErrInvalidJudge
+	}
+	if settings.Strictness == "" {
+		settings.Strictness = config.AnswerStrictness
+	}
+	if settings.Judge == "" {
+		settings.Judge = config.AnswerJudge
+	}
+	st.Settings = settings
+	return st.Settings, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// SoloService runs a practice game for a single connection. Questions follow
+// each other without a ready check and answers are judged without buzzing.
+// The game lives only on the connection and never appears as a room.
+// Every finished run is stored as a challenge that others can replay.
+type SoloService struct {
+	conn    *websocket.Conn
+	manager *RoomManager
+	mu      sync.Mutex
+	wmu     sync.Mutex
+
+	user       string
+	playlistID string
+	challenge  *ChallengeRun
+	runID      int
+	run        []RunQuestion
+	answers    []string
+	remaining  []VideoItem
+	video      VideoItem
+	accepted   []string
+	round      int
+	active     bool
+	started    time.Time
+	timer      *time.Timer
+	correct    int
+	reactions  []int64
+	closed     bool
+}
+
+// NewSoloService creates a SoloService for a connection. The game uses the
+// pack, alias and challenge stores shared through manager.
+func NewSoloService(conn *websocket.Conn, manager *RoomManager) *SoloService {
+	return &SoloService{conn: conn, manager: manager}
+}
+
+// send writes a message to the player.
+func (s *SoloService) send(msg *model.ServerMessage) {
+	msg.Timestamp = time.Now().UnixMilli()
+	resp, _ := json.Marshal(msg)
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	s.conn.WriteMessage(websocket.TextMessage, resp) // ignore errors for simplicity
+}
+
+// Close stops the question timer once the connection has gone away.
+func (s *SoloService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// ProcessMessage handles messages from the solo player.
+func (s *SoloService) ProcessMessage(mt int, msg []byte) (int, []byte) {
+	var req model.ClientMessage
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return 0, nil
+	}
+
+	switch req.Type {
+	case "playlist":
+		videos, err := s.manager.source.Videos(context.Background(), req.PlaylistID)
+		if err != nil {
+			s.send(&model.ServerMessage{Type: "error", Reason: err.Error()})
+			break
+		}
+		s.mu.Lock()
+		s.reset(req.User, req.PlaylistID, nil)
+		s.remaining = videos
+		s.mu.Unlock()
+		s.next()
+	case "challenge":
+		run, err := s.manager.Challenges().Get(req.Code)
+		if err != nil {
+			s.send(&model.ServerMessage{Type: "error", Reason: err.Error()})
+			break
+		}
+		s.mu.Lock()
+		s.reset(req.User, run.PlaylistID, run)
+		s.remaining = run.videos()
+		s.mu.Unlock()
+		s.next()
+	case "answer_text":
+		s.answer(req.Answer)
+	case "skip":
+		s.finishQuestion(OutcomeSkip)
+	}
+	return 0, nil
+}
+
+// reset starts a new game and abandons the question of any game still in
+// progress. Replaying a challenge keeps its track order. The caller must hold s.mu.
+func (s *SoloService) reset(user, playlistID string, challenge *ChallengeRun) {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.active = false
+	s.runID++
+	s.user = user
+	s.playlistID = playlistID
+	s.challenge = challenge
+	s.run = nil
+	s.round = 0
+	s.correct = 0
+	s.reactions = nil
+}
+
+// next sends the following question or the summary once the game is over.
+func (s *SoloService) next() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	limit := config.QuestionCount
+	if s.challenge != nil {
+		limit = len(s.challenge.Questions)
+	}
+	if s.round >= limit || len(s.remaining) == 0 {
+		s.finish()
+		return
+	}
+	item, rest, err := popVideo(s.remaining, s.challenge == nil)
+	s.remaining = rest
+	if err != nil {
+		s.finish()
+		return
+	}
+	s.round++
+	s.video = item
+	s.accepted = s.manager.Aliases().acceptedAnswers(item)
+	s.answers = nil
+	s.active = true
+	s.started = time.Now()
+	deadline := s.started.Add(time.Duration(config.TimeLimit) * time.Second)
+	runID, round := s.runID, s.round
+	s.timer = time.AfterFunc(time.Duration(config.TimeLimit)*time.Second, func() {
+		s.mu.Lock()
+		current := s.active && s.runID == runID && s.round == round
+		s.mu.Unlock()
+		if current {
+			s.finishQuestion(OutcomeTimeout)
+		}
+	})
+	s.mu.Unlock()
+
+	s.send(&model.ServerMessage{Type: "video", VideoID: item.ID, StartSeconds: item.Start})
+	s.send(&model.ServerMessage{Type: "start", Deadline: deadline.UnixMilli()})
+}
+
+// answer judges an answer to the current question.
+func (s *SoloService) answer(text string) {
+	s.mu.Lock()
+	if !s.active {
+		s.mu.Unlock()
+		return
+	}
+	s.answers = append(s.answers, text)
+	verdict := judgeFor(model.RoomSettings{Strictness: config.AnswerStrictness, Judge: config.AnswerJudge}).Judge(s.accepted, text)
+	if !verdict.Correct {
+		s.mu.Unlock()
+		s.send(&model.ServerMessage{Type: "answer_result", Correct: false})
+		return
+	}
+	reaction := time.Since(s.started).Milliseconds()
+	s.correct++
+	s.reactions = append(s.reactions, reaction)
+	title := s.video.Title
+	s.mu.Unlock()
+	s.send(&model.ServerMessage{Type: "answer_result", Correct: true, VideoTitle: title, MatchedAlias: verdict.Matched, Confidence: verdict.Confidence, ReactionMs: reaction})
+	s.finishQuestion(OutcomeCorrect)
+}
+
+// finishQuestion reveals the current question and moves straight on.
+func (s *SoloService) finishQuestion(outcome string) {
+	s.mu.Lock()
+	if !s.active {
+		s.mu.Unlock()
+		return
+	}
+	s.active = false
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	msg := &model.ServerMessage{
+		Type:       "reveal",
+		Outcome:    outcome,
+		VideoID:    s.video.ID,
+		VideoTitle: s.video.Title,
+		Channel:    s.video.Channel,
+	}
+	q := RunQuestion{
+		VideoID:    s.video.ID,
+		VideoTitle: s.video.Title,
+		RawTitle:   s.video.RawTitle,
+		Channel:    s.video.Channel,
+		Start:      s.video.Start,
+		Pack:       s.video.Pack,
+		Aliases:    s.video.Aliases,
+		Reading:    s.video.Reading,
+		Answers:    s.answers,
+		Outcome:    outcome,
+	}
+	if outcome == OutcomeCorrect {
+		msg.ReactionMs = s.reactions[len(s.reactions)-1]
+		msg.Points = pointsPerCorrect
+		q.Correct = true
+		q.ReactionMs = msg.ReactionMs
+	}
+	s.run = append(s.run, q)
+	s.mu.Unlock()
+	s.send(msg)
+	s.next()
+}
+
+// finish stores the run as a challenge and sends the summary, compared
+// against the original when a challenge was replayed. The caller must hold
+// s.mu, which is released before sending.
+func (s *SoloService) finish() {
+	summary := s.summary()
+	user, challenge := s.user, s.challenge
+	run := &ChallengeRun{User: user, PlaylistID: s.playlistID, Questions: s.run, CreatedAt: time.Now()}
+	s.run = nil
+	s.mu.Unlock()
+
+	if len(run.Questions) > 0 {
+		if code, err := s.manager.Challenges().Save(run); err != nil {
+			log.Printf("save challenge: %v", err)
+		} else {
+			summary.ChallengeCode = code
+		}
+	}
+	if challenge != nil {
+		summary.Comparison = compareRuns(challenge, user, run.Questions)
+	}
+	s.send(&model.ServerMessage{Type: "solo_summary", Summary: summary})
+}
+
+// summary returns the accuracy and reaction times so far. The caller must hold s.mu.
+func (s *SoloService) summary() *model.SoloSummary {
+	sum := &model.SoloSummary{Questions: s.round, Correct: s.correct}
+	if s.round > 0 {
+		sum.Accuracy = float64(s.correct) / float64(s.round)
+	}
+	if len(s.reactions) > 0 {
+		var total int64
+		for _, r := range s.reactions {
+			total += r
+		}
+		sum.AvgReactionMs = total / int64(len(s.reactions))
+	}
+	return sum
+}
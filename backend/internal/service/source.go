@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// VideoSource resolves the ID of a "playlist" message to a video pool. Question
+// packs take precedence; any other ID is treated as a YouTube playlist whose
+// videos are checked for embeddability and whose titles are cleaned of
+// decorations. Once the YouTube quota is exhausted only cached playlists can be
+// loaded and other playlists are replaced by a local pack.
+type VideoSource struct {
+	packs   *PackStore
+	titles  *TitleCleaner
+	youtube *YouTubeClient
+}
+
+// NewVideoSource creates a VideoSource.
+func NewVideoSource(packs *PackStore, titles *TitleCleaner, youtube *YouTubeClient) *VideoSource {
+	return &VideoSource{packs: packs, titles: titles, youtube: youtube}
+}
+
+// Videos returns the video pool of a pack or playlist.
+func (v *VideoSource) Videos(ctx context.Context, playlistID string) ([]VideoItem, error) {
+	p, err := v.packs.Get(playlistID)
+	if err == nil {
+		videos := p.Videos()
+		if len(videos) == 0 {
+			return nil, fmt.Errorf("pack %s has no tracks", p.Name)
+		}
+		return videos, nil
+	}
+	if !errors.Is(err, ErrPackNotFound) {
+		return nil, err
+	}
+	videos, err := v.youtube.ListPlaylistVideos(ctx, playlistID)
+	if errors.Is(err, ErrQuotaExhausted) {
+		return v.fallbackPack(playlistID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	videos, err = v.youtube.FilterEmbeddable(ctx, videos)
+	if err != nil {
+		return nil, err
+	}
+	if len(videos) == 0 {
+		return nil, fmt.Errorf("no embeddable videos found")
+	}
+	v.titles.CleanVideos(videos, playlistID)
+	return videos, nil
+}
+
+// fallbackPack returns the videos of the pack played instead of a playlist
+// that cannot be loaded because the quota is exhausted.
+func (v *VideoSource) fallbackPack(playlistID string) ([]VideoItem, error) {
+	name := config.QuotaFallbackPack
+	if name == "" {
+		list, err := v.packs.List()
+		if err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return nil, ErrQuotaExhausted
+		}
+		name = list[0].Name
+	}
+	p, err := v.packs.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("youtube quota exhausted: playing pack %s instead of playlist %s", p.Name, playlistID)
+	return p.Videos(), nil
+}
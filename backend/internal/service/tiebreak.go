@@ -0,0 +1,123 @@
+package service
+
+import (
+	"sort"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+// maxTiebreakQuestions caps sudden death so a match cannot go on forever.
+const maxTiebreakQuestions = 5
+
+// Tiebreak is the sudden-death state between players tied for first.
+type Tiebreak struct {
+	Players    []string
+	Eliminated map[string]bool
+	Winner     string
+	Questions  int
+}
+
+// canBuzz reports whether user still takes part in the tiebreaker.
+func (t *Tiebreak) canBuzz(user string) bool {
+	if t.Winner != "" || t.Eliminated[user] {
+		return false
+	}
+	for _, p := range t.Players {
+		if p == user {
+			return true
+		}
+	}
+	return false
+}
+
+// remaining returns the players that have not been eliminated.
+func (t *Tiebreak) remaining() []string {
+	var res []string
+	for _, p := range t.Players {
+		if !t.Eliminated[p] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// eliminate knocks user out and declares a winner if only one player is left.
+func (t *Tiebreak) eliminate(user string) {
+	t.Eliminated[user] = true
+	if rest := t.remaining(); len(rest) == 1 {
+		t.Winner = rest[0]
+	}
+}
+
+// done reports whether the tiebreaker has finished.
+func (t *Tiebreak) done() bool {
+	return t.Winner != "" || t.Questions >= maxTiebreakQuestions || len(t.remaining()) == 0
+}
+
+// result returns the tiebreaker as reported to clients.
+func (t *Tiebreak) result() *model.TiebreakResult {
+	res := &model.TiebreakResult{Players: t.Players, Winner: t.Winner}
+	for _, p := range t.Players {
+		if t.Eliminated[p] {
+			res.Eliminated = append(res.Eliminated, p)
+		}
+	}
+	return res
+}
+
+// tiedLeaders returns the players sharing the top score when there is more than one.
+func tiedLeaders(scores map[string]int) []string {
+	best := 0
+	var leaders []string
+	for u, sc := range scores {
+		switch {
+		case len(leaders) == 0 || sc > best:
+			best = sc
+			leaders = []string{u}
+		case sc == best:
+			leaders = append(leaders, u)
+		}
+	}
+	if len(leaders) < 2 {
+		return nil
+	}
+	sort.Strings(leaders)
+	return leaders
+}
+
+// StartTiebreak begins sudden death once the last regular question has been
+// played and several players share first place. It returns the tied players.
+func (m *RoomManager) StartTiebreak(roomID string) ([]string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.states[roomID]
+	if st == nil || st.Tiebreak != nil || st.Round < config.QuestionCount {
+		return nil, false
+	}
+	tied := tiedLeaders(st.Scores)
+	if tied == nil {
+		return nil, false
+	}
+	st.Tiebreak = &Tiebreak{Players: tied, Eliminated: make(map[string]bool)}
+	return append([]string(nil), tied...), true
+}
+
+// CanBuzz reports whether user may press the answer button. During a
+// tiebreaker only the tied players who have not been eliminated may buzz.
+// When the host judges answers they are shown the title, so they cannot buzz.
+func (m *RoomManager) CanBuzz(roomID, user string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st := m.states[roomID]
+	if st == nil {
+		return false
+	}
+	if st.Settings.HostJudging && st.Host == user {
+		return false
+	}
+	if st.Tiebreak != nil {
+		return st.Tiebreak.canBuzz(user)
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"intro-quiz/backend/internal/config"
+	"intro-quiz/backend/internal/model"
+)
+
+func TestTiebreak(t *testing.T) {
+	m, _ := testRoom(t, "r1", "alice", "bob", "carol")
+	setConfig(t, &config.QuestionCount, 1)
+	if _, err := m.NextVideo("r1"); err != nil {
+		t.Fatal(err)
+	}
+	st := m.states["r1"]
+	st.Scores["alice"], st.Scores["bob"] = 1, 1
+
+	tied, ok := m.StartTiebreak("r1")
+	if !ok || !reflect.DeepEqual(tied, []string{"alice", "bob"}) {
+		t.Fatalf("StartTiebreak() = %v, %v; want alice and bob", tied, ok)
+	}
+	if m.CanBuzz("r1", "carol") || !m.CanBuzz("r1", "alice") {
+		t.Error("CanBuzz() should only let the tied players buzz")
+	}
+	if _, over := m.FinishGame("r1"); over {
+		t.Fatal("FinishGame() ended the game before the tiebreak was decided")
+	}
+
+	if _, err := m.NextVideo("r1"); err != nil {
+		t.Fatal(err)
+	}
+	m.StartQuestion("r1")
+	if first, _ := m.AddBuzz("r1", "alice"); !first {
+		t.Fatal("AddBuzz(alice) was not first")
+	}
+	verdict, next := m.SubmitAnswer("r1", "alice", "wrong answer")
+	if verdict.Correct || next != "" {
+		t.Fatalf("SubmitAnswer() = %+v, %q; want a wrong answer with nobody next", verdict, next)
+	}
+	if st.Tiebreak.Winner != "bob" || st.Scores["bob"] != 1 || len(st.Rejections) != 0 {
+		t.Errorf("after alice was eliminated: winner %q, scores %v, rejections %v", st.Tiebreak.Winner, st.Scores, st.Rejections)
+	}
+
+	final, over := m.FinishGame("r1")
+	if !over {
+		t.Fatal("FinishGame() did not end the game after the tiebreak")
+	}
+	want := []model.Standing{
+		{User: "bob", Score: 1, Rank: 1},
+		{User: "alice", Score: 1, Rank: 2},
+		{User: "carol", Score: 0, Rank: 3},
+	}
+	if !reflect.DeepEqual(final, want) {
+		t.Errorf("FinishGame() = %+v, want %+v", final, want)
+	}
+}
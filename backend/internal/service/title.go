@@ -0,0 +1,157 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// TitleRule is a custom cleaning rule: every match of Pattern is replaced by Replace.
+type TitleRule struct {
+	Pattern string `json:"pattern"`
+	Replace string `json:"replace"`
+}
+
+// compiledRule is a TitleRule with its pattern compiled.
+type compiledRule struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// allPlaylists is the rules key that applies to every playlist.
+const allPlaylists = "*"
+
+var (
+	// decorationBrackets matches bracketed decorations such as 【MV】 or [Official Video].
+	decorationBrackets = regexp.MustCompile(`【[^】]*】|\[[^\]]*\]|［[^］]*］`)
+	// decorationParens matches parentheses holding only release decorations.
+	decorationParens = regexp.MustCompile(`(?i)[(（][^)）]*(official|video|audio|lyric|mv|pv|remaster|ver\.|version|full|公式|歌詞)[^)）]*[)）]`)
+	// featuring matches "feat. X" segments up to the next separator.
+	featuring = regexp.MustCompile(`(?i)[(（]?\b(feat|ft)\.?\s[^)）/|「」『』]*[)）]?`)
+	// decorationWords matches release labels written outside brackets.
+	decorationWords = regexp.MustCompile(`(?i)\bofficial\s+(music\s+|lyric\s+)?(video|audio|mv)\b|\b(music|lyric)\s+(video|clip)\b|\b(mv|pv)\b|(^|\s)official(\s|$)|公式`)
+	// quoteBrackets are removed while keeping the quoted song name.
+	quoteBrackets = strings.NewReplacer("「", " ", "」", " ", "『", " ", "』", " ", "“", " ", "”", " ", `"`, " ")
+	// channelDecorations are stripped from channel names before comparing them to titles.
+	channelDecorations = regexp.MustCompile(`(?i)\s*-\s*topic$|\s*official\s*(youtube\s*)?(channel)?$|\s*公式(チャンネル)?$|\s*channel$|\s*チャンネル$`)
+)
+
+// titleTrim are characters left over at either end of a cleaned title.
+const titleTrim = " -–—/／|｜・:：~〜"
+
+// TitleCleaner strips YouTube decorations from video titles. Custom regex rules
+// per playlist are read from config.TitleRulesFile, a JSON object mapping a
+// playlist ID, or "*" for all playlists, to a list of rules.
+type TitleCleaner struct {
+	mu    sync.Mutex
+	once  sync.Once
+	rules map[string][]compiledRule
+}
+
+// NewTitleCleaner creates a TitleCleaner. Rules are loaded on first use.
+func NewTitleCleaner() *TitleCleaner {
+	return &TitleCleaner{rules: make(map[string][]compiledRule)}
+}
+
+// load reads the custom rules once. Invalid patterns are logged and skipped.
+func (c *TitleCleaner) load() {
+	c.once.Do(func() {
+		if config.TitleRulesFile == "" {
+			return
+		}
+		data, err := os.ReadFile(config.TitleRulesFile)
+		if err != nil {
+			log.Printf("load title rules: %v", err)
+			return
+		}
+		var raw map[string][]TitleRule
+		if err := json.Unmarshal(data, &raw); err != nil {
+			log.Printf("load title rules: %v", err)
+			return
+		}
+		for playlist, rules := range raw {
+			for _, r := range rules {
+				re, err := regexp.Compile(r.Pattern)
+				if err != nil {
+					log.Printf("title rule %q: %v", r.Pattern, err)
+					continue
+				}
+				c.rules[playlist] = append(c.rules[playlist], compiledRule{re: re, replace: r.Replace})
+			}
+		}
+	})
+}
+
+// customRules returns the rules for a playlist, global rules first.
+func (c *TitleCleaner) customRules(playlistID string) []compiledRule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	return append(append([]compiledRule(nil), c.rules[allPlaylists]...), c.rules[playlistID]...)
+}
+
+// Clean returns the title without decorations. The raw title is returned when
+// cleaning would leave nothing.
+func (c *TitleCleaner) Clean(title, channel, playlistID string) string {
+	t := title
+	for _, r := range c.customRules(playlistID) {
+		t = r.re.ReplaceAllString(t, r.replace)
+	}
+	t = decorationBrackets.ReplaceAllString(t, " ")
+	t = decorationParens.ReplaceAllString(t, " ")
+	t = featuring.ReplaceAllString(t, " ")
+	t = stripChannel(t, cleanChannel(channel))
+	t = decorationWords.ReplaceAllString(t, " ")
+	t = quoteBrackets.Replace(t)
+	t = strings.Trim(strings.Join(strings.Fields(t), " "), titleTrim)
+	if t == "" {
+		return title
+	}
+	return t
+}
+
+// CleanVideos fills in the cleaned title of every video, keeping the raw one.
+func (c *TitleCleaner) CleanVideos(videos []VideoItem, playlistID string) {
+	for i := range videos {
+		v := &videos[i]
+		if v.RawTitle == "" {
+			v.RawTitle = v.Title
+		}
+		v.Channel = cleanChannel(v.Channel)
+		v.Title = c.Clean(v.RawTitle, v.Channel, playlistID)
+	}
+}
+
+// cleanChannel removes suffixes such as " - Topic" from a channel name.
+func cleanChannel(channel string) string {
+	return strings.TrimSpace(channelDecorations.ReplaceAllString(channel, ""))
+}
+
+// stripChannel removes the channel name when it leads or trails the title as a
+// separate part, e.g. "LiSA『紅蓮華』" or "紅蓮華 / LiSA".
+func stripChannel(title, channel string) string {
+	if channel == "" {
+		return title
+	}
+	trimmed := strings.TrimSpace(title)
+	n := len(channel)
+	if len(trimmed) > n && strings.EqualFold(trimmed[:n], channel) {
+		r, _ := utf8.DecodeRuneInString(trimmed[n:])
+		if strings.ContainsRune(titleTrim+"「『", r) {
+			return trimmed[n:]
+		}
+	}
+	if len(trimmed) > n && strings.EqualFold(trimmed[len(trimmed)-n:], channel) {
+		r, _ := utf8.DecodeLastRuneInString(trimmed[:len(trimmed)-n])
+		if strings.ContainsRune(titleTrim+"」』", r) {
+			return trimmed[:len(trimmed)-n]
+		}
+	}
+	return title
+}
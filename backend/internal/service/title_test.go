@@ -0,0 +1,24 @@
+package service
+
+import "testing"
+
+func TestTitleCleanerClean(t *testing.T) {
+	tests := []struct {
+		title   string
+		channel string
+		want    string
+	}{
+		{"【MV】YOASOBI「アイドル」Official Music Video", "YOASOBI", "アイドル"},
+		{"LiSA『紅蓮華』", "LiSA Official YouTube Channel", "紅蓮華"},
+		{"紅蓮華 / LiSA", "LiSA - Topic", "紅蓮華"},
+		{"Pretender (Official Video)", "", "Pretender"},
+		{"Shinunoga E-Wa [Official Video]", "Fujii Kaze", "Shinunoga E-Wa"},
+		{"【MV】", "", "【MV】"},
+	}
+	c := NewTitleCleaner()
+	for _, tt := range tests {
+		if got := c.Clean(tt.title, tt.channel, ""); got != tt.want {
+			t.Errorf("Clean(%q, %q) = %q, want %q", tt.title, tt.channel, got, tt.want)
+		}
+	}
+}
@@ -1,21 +1,101 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
-	"os"
+	"net/url"
+	"strings"
+	"time"
+
+	"intro-quiz/backend/internal/config"
 )
 
-// YouTubeService provides methods to interact with YouTube Data API.
-type YouTubeService struct {
-	APIKey string
+// YouTubeClient calls the YouTube Data API. The HTTP client and base URL can
+// be replaced to talk to a local fake server in development and tests. Empty
+// fields fall back to the configuration when a request is made, because the
+// shared client is created before the environment is loaded. Playlists and
+// video status are served from Cache when it is set, and calls are charged to
+// Quota when it is set.
+type YouTubeClient struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	APIKey     string
+	Cache      *YouTubeCache
+	Quota      *QuotaTracker
+}
+
+// NewYouTubeClient creates a YouTubeClient.
+func NewYouTubeClient(httpClient *http.Client, baseURL, key string) *YouTubeClient {
+	return &YouTubeClient{HTTPClient: httpClient, BaseURL: baseURL, APIKey: key}
+}
+
+// client returns the HTTP client, by default one with the configured timeout.
+func (c *YouTubeClient) client() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return &http.Client{Timeout: time.Duration(config.YouTubeTimeout) * time.Second}
 }
 
-// NewYouTubeService creates a new YouTubeService.
-func NewYouTubeService(key string) *YouTubeService {
-	return &YouTubeService{APIKey: key}
+// endpoint returns the URL of an API endpoint such as "videos".
+func (c *YouTubeClient) endpoint(name string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = config.YouTubeBaseURL
+	}
+	return strings.TrimRight(base, "/") + "/youtube/v3/" + name
+}
+
+// get calls an API endpoint such as "videos" with the query parameters and
+// decodes the JSON response into dst.
+func (c *YouTubeClient) get(ctx context.Context, endpoint string, params url.Values, dst any) error {
+	key := c.APIKey
+	if key == "" {
+		key = config.YouTubeAPIKey
+	}
+	if key == "" {
+		return fmt.Errorf("YOUTUBE_API_KEY not set")
+	}
+	if c.Quota != nil {
+		if err := c.Quota.Charge(endpoint); err != nil {
+			return err
+		}
+	}
+	params.Set("key", key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint(endpoint)+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("youtube api status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// playlistItems fetches one page of a playlist.
+func (c *YouTubeClient) playlistItems(ctx context.Context, playlistID string, maxResults int, pageToken string) (*playlistItemsResponse, error) {
+	params := url.Values{
+		"part":       {"snippet"},
+		"maxResults": {fmt.Sprint(maxResults)},
+		"playlistId": {playlistID},
+	}
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+	var data playlistItemsResponse
+	if err := c.get(ctx, "playlistItems", params, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
 }
 
 // playlistItemsResponse represents a subset of the YouTube API response.
@@ -23,35 +103,38 @@ type playlistItemsResponse struct {
 	NextPageToken string `json:"nextPageToken"`
 	Items         []struct {
 		Snippet struct {
-			Title      string `json:"title"`
-			ResourceID struct {
+			Title                  string `json:"title"`
+			VideoOwnerChannelTitle string `json:"videoOwnerChannelTitle"`
+			ResourceID             struct {
 				VideoID string `json:"videoId"`
 			} `json:"resourceId"`
 		} `json:"snippet"`
 	} `json:"items"`
 }
 
-// VideoItem represents a single video ID and title pair.
+// VideoItem represents a single video in a playlist.
 type VideoItem struct {
-	ID    string
-	Title string
+	ID      string
+	Title   string
+	Channel string
+	// RawTitle is the title as published on YouTube before cleaning.
+	RawTitle string
+	// Start is the offset in seconds the clip starts playing from.
+	Start   int
+	Aliases []string
+	// Reading is the kana reading of a title written in kanji.
+	Reading string
+	Tags    []string
+	// Pack is the question pack the video came from, empty for playlists.
+	Pack string
 }
 
 // GetFirstVideoTitle returns the first video's title from the given playlist.
-func (s *YouTubeService) GetFirstVideoTitle(playlistID string) (string, error) {
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/playlistItems?part=snippet&maxResults=1&playlistId=%s&key=%s", playlistID, s.APIKey)
-	resp, err := http.Get(url)
+func (c *YouTubeClient) GetFirstVideoTitle(ctx context.Context, playlistID string) (string, error) {
+	data, err := c.playlistItems(ctx, playlistID, 1, "")
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("youtube api status: %s", resp.Status)
-	}
-	var data playlistItemsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
-	}
 	if len(data.Items) == 0 {
 		return "", fmt.Errorf("no items found")
 	}
@@ -59,20 +142,11 @@ func (s *YouTubeService) GetFirstVideoTitle(playlistID string) (string, error) {
 }
 
 // GetFirstVideoID returns the first video's ID from the given playlist.
-func (s *YouTubeService) GetFirstVideoID(playlistID string) (string, error) {
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/playlistItems?part=snippet&maxResults=1&playlistId=%s&key=%s", playlistID, s.APIKey)
-	resp, err := http.Get(url)
+func (c *YouTubeClient) GetFirstVideoID(ctx context.Context, playlistID string) (string, error) {
+	data, err := c.playlistItems(ctx, playlistID, 1, "")
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("youtube api status: %s", resp.Status)
-	}
-	var data playlistItemsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", err
-	}
 	if len(data.Items) == 0 {
 		return "", fmt.Errorf("no items found")
 	}
@@ -80,49 +154,39 @@ func (s *YouTubeService) GetFirstVideoID(playlistID string) (string, error) {
 }
 
 // ListPlaylistVideos retrieves all video IDs and titles from the playlist.
-func (s *YouTubeService) ListPlaylistVideos(playlistID string) ([]VideoItem, error) {
+func (c *YouTubeClient) ListPlaylistVideos(ctx context.Context, playlistID string) ([]VideoItem, error) {
+	if c.Cache != nil {
+		if videos, ok := c.Cache.Playlist(playlistID); ok {
+			return videos, nil
+		}
+	}
 	var videos []VideoItem
 	pageToken := ""
 	for {
-		url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/playlistItems?part=snippet&maxResults=50&playlistId=%s&key=%s&pageToken=%s", playlistID, s.APIKey, pageToken)
-		resp, err := http.Get(url)
+		data, err := c.playlistItems(ctx, playlistID, 50, pageToken)
 		if err != nil {
 			return nil, err
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("youtube api status: %s", resp.Status)
-		}
-		var data playlistItemsResponse
-		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-			return nil, err
-		}
 		for _, it := range data.Items {
-			videos = append(videos, VideoItem{ID: it.Snippet.ResourceID.VideoID, Title: it.Snippet.Title})
+			videos = append(videos, VideoItem{ID: it.Snippet.ResourceID.VideoID, Title: it.Snippet.Title, RawTitle: it.Snippet.Title, Channel: it.Snippet.VideoOwnerChannelTitle})
 		}
 		if data.NextPageToken == "" {
 			break
 		}
 		pageToken = data.NextPageToken
 	}
+	if c.Cache != nil {
+		c.Cache.PutPlaylist(playlistID, videos)
+	}
 	return videos, nil
 }
 
 // GetRandomVideo returns a random video's ID and title from the given playlist.
-func (s *YouTubeService) GetRandomVideo(playlistID string) (string, string, error) {
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/playlistItems?part=snippet&maxResults=50&playlistId=%s&key=%s", playlistID, s.APIKey)
-	resp, err := http.Get(url)
+func (c *YouTubeClient) GetRandomVideo(ctx context.Context, playlistID string) (string, string, error) {
+	data, err := c.playlistItems(ctx, playlistID, 50, "")
 	if err != nil {
 		return "", "", err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("youtube api status: %s", resp.Status)
-	}
-	var data playlistItemsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return "", "", err
-	}
 	if len(data.Items) == 0 {
 		return "", "", fmt.Errorf("no items found")
 	}
@@ -130,7 +194,7 @@ func (s *YouTubeService) GetRandomVideo(playlistID string) (string, string, erro
 	indices := rand.Perm(len(data.Items))
 	for _, idx := range indices {
 		item := data.Items[idx].Snippet
-		embeddable, err := CheckEmbeddable(item.ResourceID.VideoID)
+		embeddable, err := c.CheckEmbeddable(ctx, item.ResourceID.VideoID)
 		if err != nil {
 			continue
 		}
@@ -141,33 +205,163 @@ func (s *YouTubeService) GetRandomVideo(playlistID string) (string, string, erro
 	return "", "", fmt.Errorf("no embeddable videos found")
 }
 
-// CheckEmbeddable verifies whether the specified video can be embedded.
-func CheckEmbeddable(videoID string) (bool, error) {
-	apiKey := os.Getenv("YOUTUBE_API_KEY")
-	if apiKey == "" {
-		return false, fmt.Errorf("YOUTUBE_API_KEY not set")
+// maxVideoIDs is the number of IDs a single videos.list call accepts.
+const maxVideoIDs = 50
+
+// videosResponse represents a subset of the videos.list response.
+type videosResponse struct {
+	Items []struct {
+		ID     string `json:"id"`
+		Status struct {
+			Embeddable bool `json:"embeddable"`
+		} `json:"status"`
+		ContentDetails struct {
+			RegionRestriction struct {
+				Allowed []string `json:"allowed"`
+				Blocked []string `json:"blocked"`
+			} `json:"regionRestriction"`
+			ContentRating struct {
+				YtRating string `json:"ytRating"`
+			} `json:"contentRating"`
+		} `json:"contentDetails"`
+	} `json:"items"`
+}
+
+// VideoStatus is what decides whether a video can be played in the embedded
+// player. Videos that no longer exist or are private have the zero status.
+type VideoStatus struct {
+	Embeddable bool `json:"embeddable"`
+	// AgeRestricted videos cannot be played in an embedded player.
+	AgeRestricted bool `json:"ageRestricted,omitempty"`
+	// RegionAllowed lists the only countries the video is available in, if set.
+	RegionAllowed []string `json:"regionAllowed,omitempty"`
+	// RegionBlocked lists the countries the video is blocked in.
+	RegionBlocked []string `json:"regionBlocked,omitempty"`
+}
+
+// Playable reports whether the video can be played in an embedded player in
+// the given country. An empty country skips the region check.
+func (s VideoStatus) Playable(country string) bool {
+	if !s.Embeddable || s.AgeRestricted {
+		return false
 	}
-	url := fmt.Sprintf("https://www.googleapis.com/youtube/v3/videos?part=status&id=%s&key=%s", videoID, apiKey)
-	resp, err := http.Get(url)
+	if country == "" {
+		return true
+	}
+	if len(s.RegionAllowed) > 0 && !containsFold(s.RegionAllowed, country) {
+		return false
+	}
+	return !containsFold(s.RegionBlocked, country)
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEmbeddable verifies whether the specified video can be played in an
+// embedded player in the configured region.
+func (c *YouTubeClient) CheckEmbeddable(ctx context.Context, videoID string) (bool, error) {
+	status, err := c.CheckEmbeddableBatch(ctx, []string{videoID})
 	if err != nil {
 		return false, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("youtube api status: %s", resp.Status)
+	return status[videoID], nil
+}
+
+// CheckEmbeddableBatch reports for each video whether it can be played in an
+// embedded player in config.YouTubeRegion, asking for up to 50 videos per
+// request. When the quota is exhausted the cached status is returned together
+// with ErrQuotaExhausted.
+func (c *YouTubeClient) CheckEmbeddableBatch(ctx context.Context, videoIDs []string) (map[string]bool, error) {
+	statuses, err := c.VideoStatuses(ctx, videoIDs)
+	if err != nil && !errors.Is(err, ErrQuotaExhausted) {
+		return nil, err
 	}
-	var result struct {
-		Items []struct {
-			Status struct {
-				Embeddable bool `json:"embeddable"`
-			} `json:"status"`
-		} `json:"items"`
+	playable := make(map[string]bool, len(statuses))
+	for id, s := range statuses {
+		playable[id] = s.Playable(config.YouTubeRegion)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false, err
+	return playable, err
+}
+
+// VideoStatuses returns the status of each video, from the cache when
+// possible. When the quota is exhausted the cached statuses are returned
+// together with ErrQuotaExhausted.
+func (c *YouTubeClient) VideoStatuses(ctx context.Context, videoIDs []string) (map[string]VideoStatus, error) {
+	if c.Cache == nil {
+		return c.fetchStatuses(ctx, videoIDs)
+	}
+	statuses, missing := c.Cache.Statuses(videoIDs)
+	if len(missing) == 0 {
+		return statuses, nil
+	}
+	fetched, err := c.fetchStatuses(ctx, missing)
+	if errors.Is(err, ErrQuotaExhausted) {
+		return statuses, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.Cache.PutStatuses(fetched)
+	for id, s := range fetched {
+		statuses[id] = s
+	}
+	return statuses, nil
+}
+
+// fetchStatuses asks the API for the status and content details of the videos.
+func (c *YouTubeClient) fetchStatuses(ctx context.Context, videoIDs []string) (map[string]VideoStatus, error) {
+	statuses := make(map[string]VideoStatus, len(videoIDs))
+	for start := 0; start < len(videoIDs); start += maxVideoIDs {
+		end := start + maxVideoIDs
+		if end > len(videoIDs) {
+			end = len(videoIDs)
+		}
+		chunk := videoIDs[start:end]
+		var result videosResponse
+		params := url.Values{"part": {"status,contentDetails"}, "id": {strings.Join(chunk, ",")}}
+		if err := c.get(ctx, "videos", params, &result); err != nil {
+			return nil, err
+		}
+		for _, id := range chunk {
+			statuses[id] = VideoStatus{}
+		}
+		for _, it := range result.Items {
+			statuses[it.ID] = VideoStatus{
+				Embeddable:    it.Status.Embeddable,
+				AgeRestricted: it.ContentDetails.ContentRating.YtRating == "ytAgeRestricted",
+				RegionAllowed: it.ContentDetails.RegionRestriction.Allowed,
+				RegionBlocked: it.ContentDetails.RegionRestriction.Blocked,
+			}
+		}
 	}
-	if len(result.Items) == 0 {
-		return false, fmt.Errorf("no items found")
+	return statuses, nil
+}
+
+// FilterEmbeddable returns the videos that can be embedded, keeping their order.
+// When the quota is exhausted, videos whose status is not cached are kept.
+func (c *YouTubeClient) FilterEmbeddable(ctx context.Context, videos []VideoItem) ([]VideoItem, error) {
+	ids := make([]string, len(videos))
+	for i, v := range videos {
+		ids[i] = v.ID
+	}
+	status, err := c.CheckEmbeddableBatch(ctx, ids)
+	exhausted := errors.Is(err, ErrQuotaExhausted)
+	if err != nil && !exhausted {
+		return nil, err
+	}
+	var playable []VideoItem
+	for _, v := range videos {
+		ok, known := status[v.ID]
+		if ok || (exhausted && !known) {
+			playable = append(playable, v)
+		}
 	}
-	return result.Items[0].Status.Embeddable, nil
+	return playable, nil
 }
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"intro-quiz/backend/internal/config"
+)
+
+// fakeYouTube is a YouTube Data API stand-in that serves a fixed playlist and
+// video list and records the requests it receives.
+type fakeYouTube struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	// playlist is served two videos per page.
+	playlist []VideoItem
+	videos   map[string]map[string]any
+}
+
+// newFakeYouTube starts a fake server and a client pointing at it.
+func newFakeYouTube(t *testing.T, f *fakeYouTube) *YouTubeClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	return NewYouTubeClient(srv.Client(), srv.URL, "test-key")
+}
+
+func (f *fakeYouTube) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r)
+	f.mu.Unlock()
+	if r.URL.Query().Get("key") != "test-key" {
+		http.Error(w, "bad key", http.StatusForbidden)
+		return
+	}
+	var resp map[string]any
+	switch r.URL.Path {
+	case "/youtube/v3/playlistItems":
+		start := 0
+		if tok := r.URL.Query().Get("pageToken"); tok != "" {
+			start = int(tok[0] - '0')
+		}
+		end := start + 2
+		if end > len(f.playlist) {
+			end = len(f.playlist)
+		}
+		var items []map[string]any
+		for _, v := range f.playlist[start:end] {
+			items = append(items, map[string]any{"snippet": map[string]any{
+				"title":                  v.Title,
+				"videoOwnerChannelTitle": v.Channel,
+				"resourceId":             map[string]any{"videoId": v.ID},
+			}})
+		}
+		resp = map[string]any{"items": items}
+		if end < len(f.playlist) {
+			resp["nextPageToken"] = string(rune('0' + end))
+		}
+	case "/youtube/v3/videos":
+		var items []map[string]any
+		for _, id := range strings.Split(r.URL.Query().Get("id"), ",") {
+			if v, ok := f.videos[id]; ok {
+				items = append(items, v)
+			}
+		}
+		resp = map[string]any{"items": items}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// calls returns how many requests were made to the endpoint.
+func (f *fakeYouTube) calls(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, r := range f.requests {
+		if r.URL.Path == "/youtube/v3/"+endpoint {
+			n++
+		}
+	}
+	return n
+}
+
+func TestYouTubeClientListPlaylistVideos(t *testing.T) {
+	f := &fakeYouTube{playlist: []VideoItem{
+		{ID: "v1", Title: "Pretender", Channel: "Official髭男dism"},
+		{ID: "v2", Title: "アイドル", Channel: "YOASOBI"},
+		{ID: "v3", Title: "紅蓮華", Channel: "LiSA"},
+	}}
+	c := newFakeYouTube(t, f)
+	videos, err := c.ListPlaylistVideos(context.Background(), "PL1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(videos) != 3 {
+		t.Fatalf("ListPlaylistVideos() = %+v, want 3 videos", videos)
+	}
+	for i, v := range videos {
+		want := f.playlist[i]
+		if v.ID != want.ID || v.Title != want.Title || v.RawTitle != want.Title || v.Channel != want.Channel {
+			t.Errorf("video %d = %+v, want %+v", i, v, want)
+		}
+	}
+	if got := f.calls("playlistItems"); got != 2 {
+		t.Errorf("playlistItems called %d times, want 2 pages", got)
+	}
+
+	c.APIKey = "wrong-key"
+	if _, err := c.ListPlaylistVideos(context.Background(), "PL1"); err == nil {
+		t.Error("ListPlaylistVideos() with a rejected key succeeded")
+	}
+}
+
+// embeddableVideo is a videos.list item of a plain embeddable video.
+func embeddableVideo(id string) map[string]any {
+	return map[string]any{"id": id, "status": map[string]any{"embeddable": true}}
+}
+
+func TestYouTubeClientCheckEmbeddableBatch(t *testing.T) {
+	f := &fakeYouTube{videos: make(map[string]map[string]any)}
+	var ids []string
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("v%03d", i)
+		ids = append(ids, id)
+		if i%10 != 0 {
+			f.videos[id] = embeddableVideo(id)
+		}
+	}
+	f.videos["v005"] = map[string]any{"id": "v005", "status": map[string]any{"embeddable": false}}
+	c := newFakeYouTube(t, f)
+
+	got, err := c.CheckEmbeddableBatch(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := f.calls("videos"); calls != 3 {
+		t.Errorf("videos called %d times for %d IDs, want 3", calls, len(ids))
+	}
+	for _, r := range f.requests {
+		if n := len(strings.Split(r.URL.Query().Get("id"), ",")); n > maxVideoIDs {
+			t.Errorf("request asked for %d IDs, want at most %d", n, maxVideoIDs)
+		}
+	}
+	if len(got) != len(ids) {
+		t.Fatalf("CheckEmbeddableBatch() returned %d results, want %d", len(got), len(ids))
+	}
+	for _, tt := range []struct {
+		id   string
+		want bool
+	}{{"v001", true}, {"v005", false}, {"v010", false}, {"v119", true}} {
+		if got[tt.id] != tt.want {
+			t.Errorf("CheckEmbeddableBatch()[%q] = %v, want %v", tt.id, got[tt.id], tt.want)
+		}
+	}
+}
+
+func TestYouTubeClientCache(t *testing.T) {
+	f := &fakeYouTube{
+		playlist: []VideoItem{{ID: "v1", Title: "Pretender"}, {ID: "v2", Title: "Lemon"}},
+		videos:   map[string]map[string]any{"v1": embeddableVideo("v1")},
+	}
+	c := newFakeYouTube(t, f)
+	c.Cache = NewYouTubeCache()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if videos, err := c.ListPlaylistVideos(ctx, "PL1"); err != nil || len(videos) != 2 {
+			t.Fatalf("ListPlaylistVideos() = %+v, %v", videos, err)
+		}
+		got, err := c.CheckEmbeddableBatch(ctx, []string{"v1", "v2"})
+		if err != nil || !got["v1"] || got["v2"] {
+			t.Fatalf("CheckEmbeddableBatch() = %v, %v", got, err)
+		}
+	}
+	if calls := f.calls("playlistItems"); calls != 1 {
+		t.Errorf("playlistItems called %d times, want 1", calls)
+	}
+	if calls := f.calls("videos"); calls != 1 {
+		t.Errorf("videos called %d times, want 1", calls)
+	}
+	stats := c.Cache.Stats()
+	if stats.PlaylistHits != 1 || stats.VideoHits != 2 || stats.VideoMisses != 2 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestYouTubeClientQuota(t *testing.T) {
+	useDataDir(t)
+	old := config.YouTubeQuotaBudget
+	config.YouTubeQuotaBudget = 2
+	t.Cleanup(func() { config.YouTubeQuotaBudget = old })
+
+	f := &fakeYouTube{videos: map[string]map[string]any{
+		"v1": embeddableVideo("v1"),
+		"v2": {"id": "v2", "status": map[string]any{"embeddable": false}},
+	}}
+	c := newFakeYouTube(t, f)
+	c.Cache = NewYouTubeCache()
+	c.Quota = NewQuotaTracker()
+	ctx := context.Background()
+
+	if _, err := c.CheckEmbeddableBatch(ctx, []string{"v1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CheckEmbeddableBatch(ctx, []string{"v2"}); err != nil {
+		t.Fatal(err)
+	}
+	if u := c.Quota.Usage(); u.Used != 2 || u.Calls["videos"] != 2 || !u.Exhausted {
+		t.Errorf("Usage() = %+v, want 2 videos calls and the budget exhausted", u)
+	}
+
+	// 予算切れでもキャッシュ済みの判定は使い、未確認の動画は残す
+	videos := []VideoItem{{ID: "v1"}, {ID: "v2"}, {ID: "v3"}}
+	got, err := c.FilterEmbeddable(ctx, videos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != "v1" || got[1].ID != "v3" {
+		t.Errorf("FilterEmbeddable() = %+v, want v1 and v3", got)
+	}
+	if _, err := c.CheckEmbeddableBatch(ctx, []string{"v3"}); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("CheckEmbeddableBatch() over budget = %v, want ErrQuotaExhausted", err)
+	}
+	if calls := f.calls("videos"); calls != 2 {
+		t.Errorf("videos called %d times, want no call over budget", calls)
+	}
+}
+
+func TestYouTubeClientRegionAndAge(t *testing.T) {
+	old := config.YouTubeRegion
+	config.YouTubeRegion = "JP"
+	t.Cleanup(func() { config.YouTubeRegion = old })
+
+	f := &fakeYouTube{videos: map[string]map[string]any{
+		"open": embeddableVideo("open"),
+		"blocked": {"id": "blocked", "status": map[string]any{"embeddable": true},
+			"contentDetails": map[string]any{"regionRestriction": map[string]any{"blocked": []string{"JP", "KR"}}}},
+		"us": {"id": "us", "status": map[string]any{"embeddable": true},
+			"contentDetails": map[string]any{"regionRestriction": map[string]any{"allowed": []string{"US"}}}},
+		"jp": {"id": "jp", "status": map[string]any{"embeddable": true},
+			"contentDetails": map[string]any{"regionRestriction": map[string]any{"allowed": []string{"jp"}}}},
+		"age": {"id": "age", "status": map[string]any{"embeddable": true},
+			"contentDetails": map[string]any{"contentRating": map[string]any{"ytRating": "ytAgeRestricted"}}},
+	}}
+	c := newFakeYouTube(t, f)
+	got, err := c.CheckEmbeddableBatch(context.Background(), []string{"open", "blocked", "us", "jp", "age"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"open": true, "blocked": false, "us": false, "jp": true, "age": false}
+	for id, w := range want {
+		if got[id] != w {
+			t.Errorf("CheckEmbeddableBatch()[%q] = %v, want %v", id, got[id], w)
+		}
+	}
+
+	statuses, err := c.VideoStatuses(context.Background(), []string{"us"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := statuses["us"]; !s.Playable("US") || !s.Playable("") || s.Playable("JP") {
+		t.Errorf("Playable() of %+v does not follow the allowed regions", s)
+	}
+}
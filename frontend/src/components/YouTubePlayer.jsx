@@ -1,7 +1,7 @@
 import YouTube from "react-youtube";
 import { useEffect, useRef, useState } from "react";
 
-export default function YouTubePlayer({ videoId, playing, onPause }) {
+export default function YouTubePlayer({ videoId, start, playing, onPause }) {
   const playerRef = useRef(null);
   const [ready, setReady] = useState(false);
 
@@ -11,6 +11,7 @@ export default function YouTubePlayer({ videoId, playing, onPause }) {
     playerVars: {
       modestbranding: 1,
       rel: 0,
+      start: start || 0,
     },
   };
 
@@ -26,8 +26,12 @@ export default function RoomPage() {
   const [playing, setPlaying] = useState(false);
   const [pauseInfo, setPauseInfo] = useState("");
   const [videoId, setVideoId] = useState("M7lc1UVf-VE");
+  const [videoStart, setVideoStart] = useState(0);
   const [playlistInput, setPlaylistInput] = useState("");
   const [answerText, setAnswerText] = useState("");
+  const [judgeRequest, setJudgeRequest] = useState(null);
+  const [canAppeal, setCanAppeal] = useState(false);
+  const [appeal, setAppeal] = useState(null);
   const timerRef = useRef(null);
   const { connect, send } = useWebSocket(WS_URL);
 
@@ -62,21 +66,67 @@ export default function RoomPage() {
         } else if (data.type === "answer") {
           setPlaying(false);
           setPauseInfo(`${data.user}さんが解答ボタンを押しました - 再生停止中`);
+        } else if (data.type === "judge_request") {
+          setJudgeRequest(data);
+        } else if (data.type === "judging") {
+          setPauseInfo(`${data.user}さんの回答を判定中…`);
         } else if (data.type === "answer_result") {
+          setJudgeRequest(null);
           if (data.correct) {
             setQuestionActive(false);
             setWinner(null);
-            setPauseInfo(`${data.user}さんの正解！ 正解は${data.videoTitle}`);
+            const alias =
+              data.matchedAlias && data.matchedAlias !== data.videoTitle
+                ? `（${data.matchedAlias}）`
+                : "";
+            setPauseInfo(
+              `${data.user}さんの正解！ 正解は${data.videoTitle}${alias}`,
+            );
           } else {
             setPauseInfo(`${data.user}さんは不正解`);
             setWinner(null);
+            if (data.user === userName && data.deadline) {
+              setCanAppeal(true);
+              setTimeout(
+                () => setCanAppeal(false),
+                data.deadline - Date.now(),
+              );
+            }
           }
+        } else if (data.type === "appeal") {
+          setAppeal(data.user === userName ? null : data);
+          setPauseInfo(
+            `${data.user}さんが「${data.text}」の判定に異議を申し立てました`,
+          );
+        } else if (data.type === "appeal_result") {
+          setAppeal(null);
+          setPauseInfo(
+            data.correct
+              ? `異議が認められ、${data.user}さんの「${data.text}」は正解になりました`
+              : `${data.user}さんの異議は認められませんでした`,
+          );
+        } else if (data.type === "reveal") {
+          setJudgeRequest(null);
+          setQuestionActive(false);
+          setWinner(null);
+          setPlaying(false);
+          clearInterval(timerRef.current);
+          setPauseInfo(
+            data.user
+              ? `${data.user}さんの正解！ 正解は${data.videoTitle}（${data.channel}）`
+              : `正解は${data.videoTitle}（${data.channel}）`,
+          );
         } else if (data.type === "ready_state") {
           setReadyStates(data.readyUsers);
         } else if (data.type === "buzz_order") {
           setBuzzOrder(data.buzzOrder);
+        } else if (data.type === "quota_exhausted") {
+          setPauseInfo(
+            "YouTube APIの上限に達したため、キャッシュ済みの曲かローカルの問題パックから出題します",
+          );
         } else if (data.type === "video") {
           setVideoId(data.videoId);
+          setVideoStart(data.startSeconds || 0);
         }
         addMessage(event.data);
       },
@@ -111,6 +161,21 @@ export default function RoomPage() {
     setAnswerText("");
   };
 
+  const sendJudge = (accept) => {
+    send(JSON.stringify({ type: "judge", user: name, accept }));
+    setJudgeRequest(null);
+  };
+
+  const sendAppeal = () => {
+    send(JSON.stringify({ type: "appeal", user: name }));
+    setCanAppeal(false);
+  };
+
+  const sendAppealVote = (accept) => {
+    send(JSON.stringify({ type: "appeal_vote", user: name, accept }));
+    setAppeal(null);
+  };
+
   useEffect(() => {
     if (!questionActive && timerRef.current) {
       clearInterval(timerRef.current);
@@ -139,7 +204,7 @@ export default function RoomPage() {
           </div>
           {playing && <p>再生中…</p>}
           {pauseInfo && <p>{pauseInfo}</p>}
-          <YouTubePlayer videoId={videoId} playing={playing} />
+          <YouTubePlayer videoId={videoId} start={videoStart} playing={playing} />
           {questionActive && (
             <div>
               <p>制限時間: {timeLeft}秒</p>
@@ -157,6 +222,26 @@ export default function RoomPage() {
               <button onClick={sendAnswer}>送信</button>
             </div>
           )}
+          {judgeRequest && (
+            <div>
+              <p>
+                {judgeRequest.user}さんの回答「{judgeRequest.text}」（正解:
+                {judgeRequest.videoTitle}）
+              </p>
+              <button onClick={() => sendJudge(true)}>正解</button>
+              <button onClick={() => sendJudge(false)}>不正解</button>
+            </div>
+          )}
+          {canAppeal && <button onClick={sendAppeal}>異議を申し立てる</button>}
+          {appeal && (
+            <div>
+              <p>
+                {appeal.user}さんの回答「{appeal.text}」を正解にしますか？
+              </p>
+              <button onClick={() => sendAppealVote(true)}>賛成</button>
+              <button onClick={() => sendAppealVote(false)}>反対</button>
+            </div>
+          )}
           {buzzOrder.length > 0 && (
             <div>
               <p>押した順:</p>